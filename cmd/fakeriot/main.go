@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot/riottest"
+)
+
+// オフライン開発用の Riot API フェイクサーバー
+// 例: go run ./cmd/fakeriot -fixtures testdata/fakeriot -rate-limit-error-rate 0.05
+// クライアント側は RIOT_BASE_URL=http://localhost:9090 を指定して接続する
+func main() {
+	addr := flag.String("addr", ":9090", "待ち受けアドレス")
+	fixtureDir := flag.String("fixtures", "testdata/fakeriot", "フィクスチャのディレクトリ")
+	apiKey := flag.String("api-key", "", "検証する API キー（空の場合はヘッダーの有無のみ確認）")
+	latency := flag.Duration("latency", 0, "レスポンスまでの遅延")
+	rateLimitErrorRate := flag.Float64("rate-limit-error-rate", 0, "ランダムに 429 を返す確率（0-1）")
+	serverErrorRate := flag.Float64("server-error-rate", 0, "ランダムに 5xx を返す確率（0-1）")
+	retryAfter := flag.Duration("retry-after", 2*time.Second, "ランダムな 429 に付与する Retry-After")
+	appRateLimit := flag.String("app-rate-limit", "20:1,100:120", "アプリ全体の制限（回数:秒 をカンマ区切り、空で無制限）")
+	methodRateLimit := flag.String("method-rate-limit", "", "メソッドごとの制限（同形式）")
+	flag.Parse()
+
+	server, err := riottest.NewServer(riottest.Config{
+		FixtureDir:         *fixtureDir,
+		APIKey:             *apiKey,
+		Latency:            *latency,
+		RateLimitErrorRate: *rateLimitErrorRate,
+		ServerErrorRate:    *serverErrorRate,
+		RetryAfter:         *retryAfter,
+		AppRateLimit:       *appRateLimit,
+		MethodRateLimit:    *methodRateLimit,
+	})
+	if err != nil {
+		log.Fatalf("フェイクサーバー初期化エラー: %v", err)
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("%s %s", r.Method, r.URL.RequestURI())
+		server.ServeHTTP(w, r)
+	})
+
+	log.Printf("Fake Riot API server starting on %s (fixtures: %s)", *addr, *fixtureDir)
+	log.Fatal(http.ListenAndServe(*addr, handler))
+}
@@ -2,7 +2,10 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"os/signal"
@@ -12,9 +15,14 @@ import (
 	"github.com/MicronGit/Summoner-Analysis/internal/config"
 	"github.com/MicronGit/Summoner-Analysis/internal/output"
 	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+	"github.com/MicronGit/Summoner-Analysis/internal/staticdata"
+	"github.com/MicronGit/Summoner-Analysis/internal/store"
 )
 
 func main() {
+	timelines := flag.Bool("timelines", false, "マッチタイムラインを取得してレーン戦を分析する（1試合につき1リクエスト追加）")
+	flag.Parse()
+
 	// キャンセル可能なコンテキスト作成
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -40,7 +48,50 @@ func main() {
 		log.Fatal("RIOT_API_KEY が設定されていません")
 	}
 
-	client := riot.NewClient(cfg.RiotAPIKey, cfg.Region)
+	clientOpts := []riot.Option{
+		riot.WithUserAgent("Summoner-Analysis"),
+		riot.WithProgress(newProgressBar(os.Stdout)),
+	}
+	if cfg.RiotBaseURL != "" {
+		clientOpts = append(clientOpts, riot.WithBaseURL(cfg.RiotBaseURL))
+	}
+	if cfg.Platform != "" {
+		clientOpts = append(clientOpts, riot.WithPlatform(cfg.Platform))
+	}
+
+	client := riot.NewClient(cfg.RiotAPIKey, cfg.Region, clientOpts...)
+	client.FetchWorkers = cfg.FetchWorkers
+
+	if _, err := client.Routing(); err != nil {
+		log.Fatalf("REGION / PLATFORM の設定が不正です: %v", err)
+	}
+
+	// マッチキャッシュ設定
+	var matchStore *store.FileStore
+	if cfg.StoreDir != "" {
+		fileStore, err := store.NewFileStore(cfg.StoreDir, cfg.StoreMaxMatches)
+		if err != nil {
+			log.Fatalf("マッチキャッシュ初期化エラー: %v", err)
+		}
+		matchStore = fileStore
+		client.MatchStore = fileStore
+		client.HistoryStore = fileStore
+		client.TimelineStore = fileStore
+		client.LeagueHistoryStore = fileStore
+	}
+
+	// 静的データ（取り込んでいない場合は統計データに名前・アイコンを含めない）
+	var staticData *staticdata.Store
+	if cfg.StaticDataDir != "" {
+		opened, err := staticdata.Open(cfg.StaticDataDir, cfg.StaticDataLocale)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			log.Printf("静的データ読み込みエラー: %v", err)
+		case len(opened.Versions()) > 0:
+			staticData = opened
+		}
+	}
 
 	gameName := "そっちん"
 	tagLine := "JP1"
@@ -59,7 +110,9 @@ func main() {
 	// ランク戦分析データ取得
 	fmt.Println("2. ランク戦マッチ履歴と詳細データを取得中...")
 	matchCount := 100
-	analysis, err := client.GetPlayerRankedAnalysisWithContext(ctx, account, matchCount)
+	opts, _ := riot.AnalysisOptionsForGameType("ranked", matchCount)
+	opts.IncludeTimelines = *timelines // レーン戦分析用
+	analysis, err := client.GetPlayerAnalysis(ctx, account, opts)
 	if err != nil {
 		if ctx.Err() != nil {
 			fmt.Printf("処理がキャンセルまたはタイムアウトしました: %v\n", ctx.Err())
@@ -70,6 +123,12 @@ func main() {
 
 	fmt.Printf("   取得完了: %d試合のランク戦データを分析\n", analysis.TotalMatches)
 
+	if matchStore != nil {
+		cacheStats := matchStore.Stats()
+		fmt.Printf("   キャッシュ: ヒット %d / ミス %d（保存済み %d試合）\n",
+			cacheStats.Hits, cacheStats.Misses, cacheStats.Entries)
+	}
+
 	if analysis.TotalMatches == 0 {
 		fmt.Println("ランク戦の履歴が見つかりませんでした。")
 		return
@@ -86,7 +145,7 @@ func main() {
 	}
 
 	// 統計データ出力
-	statsPath, err := output.SavePlayerStats(analysis, outputDir)
+	statsPath, err := output.SavePlayerStats(analysis, outputDir, staticData)
 	if err != nil {
 		log.Fatalf("統計データ出力エラー: %v", err)
 	}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+// 進捗バーの幅（文字数）
+const progressBarWidth = 30
+
+// 進捗イベントをターミナルにプログレスバーとして表示
+type progressBar struct {
+	mu     sync.Mutex
+	out    io.Writer
+	active bool // プログレスバーを表示中（次のメッセージの前に改行が必要）
+}
+
+func newProgressBar(out io.Writer) *progressBar {
+	return &progressBar{out: out}
+}
+
+func (p *progressBar) Report(event riot.ProgressEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch event.Type {
+	case riot.ProgressAdvance:
+		p.draw(event)
+	case riot.ProgressPhaseStart:
+		if event.Message != "" {
+			p.println("   " + event.Message + "...")
+		}
+	case riot.ProgressPhaseDone:
+		if event.Message != "" {
+			p.println("   ✅ " + event.Message)
+		}
+	case riot.ProgressSkipped:
+		p.println(fmt.Sprintf("   ⚠️  %s %s の取得に失敗: %v", event.Phase.Label(), event.MatchID, event.Err))
+	case riot.ProgressRateLimitWait:
+		p.println(fmt.Sprintf("   ⏳ レート制限に達しました（%s）。%v 待機中...", event.Routing, event.Wait.Round(time.Second)))
+	case riot.ProgressRetry:
+		p.println(fmt.Sprintf("   🔁 %s: %v後に再試行 %s", event.Message, event.Wait, errorSuffix(event.Err)))
+	case riot.ProgressWarning:
+		p.println(fmt.Sprintf("   ⚠️  %s: %v", event.Message, event.Err))
+	case riot.ProgressInfo:
+		p.println("   " + event.Message)
+	}
+}
+
+// 同じ行にプログレスバーを上書き表示
+func (p *progressBar) draw(event riot.ProgressEvent) {
+	ratio := float64(event.Fetched) / float64(max(event.Total, 1))
+	// 取得数が総数を超えた場合や負の値でも幅に収める
+	filled := min(max(int(ratio*progressBarWidth), 0), progressBarWidth)
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+
+	line := fmt.Sprintf("\r   %s [%s] %d/%d (%.1f%%) 経過 %v 残り %v",
+		event.Phase.Label(), bar, event.Fetched, event.Total, ratio*100,
+		event.Elapsed.Round(time.Second), event.ETA.Round(time.Second))
+	if event.Skipped > 0 {
+		line += fmt.Sprintf(" 失敗 %d", event.Skipped)
+	}
+
+	// 前回の表示より短い場合に残る文字を消す
+	fmt.Fprint(p.out, line+"\033[K")
+	p.active = true
+
+	if event.Fetched >= event.Total {
+		fmt.Fprintln(p.out)
+		p.active = false
+	}
+}
+
+func (p *progressBar) println(message string) {
+	if p.active {
+		fmt.Fprintln(p.out)
+		p.active = false
+	}
+	fmt.Fprintln(p.out, message)
+}
+
+func errorSuffix(err error) string {
+	if err == nil {
+		return ""
+	}
+	return fmt.Sprintf("(%v)", err)
+}
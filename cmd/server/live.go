@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/analysis"
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+// 試合中の参加者1人あたりに取得する直近の試合数
+const (
+	defaultLiveMatchCount = 5
+	maxLiveMatchCount     = 20
+)
+
+// 試合中のプレイヤーと同じ試合の参加者全員の直近成績を返す
+// GET /api/live/{gameName}/{tagLine}?count=5&platform=jp1
+func (s *Server) handleLiveGame(w http.ResponseWriter, r *http.Request) {
+	s.enableCORS(w)
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	gameName, tagLine := r.PathValue("gameName"), r.PathValue("tagLine")
+	if gameName == "" || tagLine == "" {
+		s.sendError(w, "GameName and TagLine are required", http.StatusBadRequest)
+		return
+	}
+
+	count := defaultLiveMatchCount
+	if v := r.URL.Query().Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			s.sendError(w, fmt.Sprintf("Invalid count: %s", v), http.StatusBadRequest)
+			return
+		}
+		count = min(n, maxLiveMatchCount)
+	}
+
+	client, err := s.client.ForRouting(r.URL.Query().Get("region"), r.URL.Query().Get("platform"))
+	if err != nil {
+		s.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Minute)
+	defer cancel()
+
+	account, err := client.GetAccountByRiotID(gameName, tagLine)
+	if err != nil {
+		log.Printf("Account fetch error: %v", err)
+		s.sendRiotError(w, "アカウント取得エラー", err)
+		return
+	}
+
+	game, err := client.GetActiveGameWithContext(ctx, account.PUUID)
+	if errors.Is(err, riot.ErrNotFound) {
+		s.sendError(w, fmt.Sprintf("%s#%s は試合中ではありません", gameName, tagLine), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Active game fetch error: %v", err)
+		s.sendRiotError(w, "試合情報取得エラー", err)
+		return
+	}
+
+	// ボットは直近成績がないため対象外
+	var players []riot.ActiveGameParticipant
+	for _, p := range game.Participants {
+		if !p.Bot && p.PUUID != "" {
+			players = append(players, p)
+		}
+	}
+
+	// ランクとマッチIDは参加者ごとに1リクエスト（試合の詳細はキャッシュを確認してから見積もる）
+	routing, err := client.Routing()
+	if err != nil {
+		s.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := checkLiveBudget(client, routing.Platform, riot.MethodLeagueEntriesByPUUID, len(players)); err != nil {
+		s.sendError(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+	if err := checkLiveBudget(client, routing.Region, riot.MethodMatchIDs, len(players)); err != nil {
+		s.sendError(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+
+	log.Printf("Scouting live game %d for %s#%s (%d participants, %d matches each)",
+		game.GameID, gameName, tagLine, len(players), count)
+
+	scout, err := scoutParticipants(ctx, client, players, count)
+	if err != nil {
+		s.sendRiotError(w, "直近成績取得エラー", err)
+		return
+	}
+
+	report := analysis.BuildLiveGameReport(game, scout.histories, scout.leagues, scout.errs)
+	s.resolveChampionNames(report)
+
+	s.sendSuccess(w, report)
+}
+
+// 試合履歴から名前が分からなかったチャンピオンを静的データで補う
+func (s *Server) resolveChampionNames(report *analysis.LiveGameReport) {
+	if s.staticData == nil {
+		return
+	}
+
+	snapshot, err := s.staticData.Latest()
+	if err != nil {
+		log.Printf("Static data load error: %v", err)
+		return
+	}
+
+	for i := range report.Participants {
+		participant := &report.Participants[i]
+		if champion, ok := snapshot.Champions[participant.ChampionID]; ok && participant.ChampionName == "" {
+			participant.ChampionName = champion.ID
+		}
+	}
+}
+
+// 取得に必要なリクエスト数がレート制限の残りに収まるか確認（超える場合は riot.ErrRateLimited をラップして返す）
+func checkLiveBudget(client *riot.Client, routing, method string, requests int) error {
+	if budget := client.RateLimiter.Budget(routing, method); requests > budget {
+		return fmt.Errorf("参加者の直近成績の取得に%dリクエスト（%s, %s）が必要ですが、レート制限の残りは%dです。count を減らすか時間をおいて再試行してください: %w",
+			requests, routing, method, budget, riot.ErrRateLimited)
+	}
+	return nil
+}
+
+// 試合中の参加者全員の取得結果（キーは PUUID）
+type liveScout struct {
+	histories map[string][]riot.MatchDetail
+	leagues   map[string][]riot.LeagueEntry
+	errs      map[string]error
+}
+
+// 参加者ごとのランクとマッチIDを並行取得し、試合の詳細は全員分をまとめて並行取得する
+// デュオなど複数の参加者が出ている試合は1回だけ取得し、取得に失敗した試合は除く
+// 分析時に差分同期する保存済みの履歴を上書きしないよう、マッチIDの一覧は保存しない
+func scoutParticipants(ctx context.Context, client *riot.Client, players []riot.ActiveGameParticipant, count int) (*liveScout, error) {
+	leagues := make([][]riot.LeagueEntry, len(players))
+	matchIDs := make([]riot.MatchHistory, len(players))
+	historyErrs := make([]error, len(players))
+
+	var wg sync.WaitGroup
+	for i, p := range players {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// ランクが取得できなくても直近成績は返す
+			entries, err := client.GetLeagueEntriesByPUUIDWithContext(ctx, p.PUUID)
+			if err != nil {
+				log.Printf("League fetch error for %s: %v", p.RiotID, err)
+			}
+			leagues[i] = entries
+
+			matchIDs[i], historyErrs[i] = client.GetMatchHistoryWithContext(ctx, p.PUUID, riot.MatchHistoryOptions{Count: count})
+		}()
+	}
+	wg.Wait()
+
+	// タイムアウト・キャンセル時は試合の詳細も取得できないため打ち切る
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// キャッシュ済みの試合はリクエスト数に含めない
+	details := make(map[string]*riot.MatchDetail)
+	var uncached []string
+	seen := make(map[string]bool)
+	for _, ids := range matchIDs {
+		for _, id := range ids {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			if detail := cachedMatch(client, id); detail != nil {
+				details[id] = detail
+			} else {
+				uncached = append(uncached, id)
+			}
+		}
+	}
+
+	routing, err := client.Routing()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLiveBudget(client, routing.Region, riot.MethodMatchDetail, len(uncached)); err != nil {
+		return nil, err
+	}
+
+	results, err := client.FetchMatchDetailsWithContext(ctx, uncached)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		if result.Err != nil {
+			log.Printf("Match fetch error for %s: %v", result.MatchID, result.Err)
+			continue
+		}
+		details[result.MatchID] = result.Detail
+	}
+
+	scout := &liveScout{
+		histories: make(map[string][]riot.MatchDetail),
+		leagues:   make(map[string][]riot.LeagueEntry),
+		errs:      make(map[string]error),
+	}
+	for i, p := range players {
+		scout.leagues[p.PUUID] = leagues[i]
+
+		if historyErrs[i] != nil {
+			log.Printf("Recent matches fetch error for %s: %v", p.RiotID, historyErrs[i])
+			scout.errs[p.PUUID] = fmt.Errorf("マッチ履歴取得エラー: %w", historyErrs[i])
+			continue
+		}
+
+		matches := make([]riot.MatchDetail, 0, len(matchIDs[i]))
+		for _, id := range matchIDs[i] {
+			if detail, ok := details[id]; ok {
+				matches = append(matches, *detail)
+			}
+		}
+		scout.histories[p.PUUID] = matches
+	}
+
+	return scout, nil
+}
+
+// キャッシュ済みのマッチ詳細（キャッシュがない場合・未保存の場合は nil）
+func cachedMatch(client *riot.Client, matchID string) *riot.MatchDetail {
+	if client.MatchStore == nil {
+		return nil
+	}
+	detail, ok, err := client.MatchStore.GetMatch(matchID)
+	if err != nil || !ok {
+		return nil
+	}
+	return detail
+}
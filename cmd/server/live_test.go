@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/analysis"
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+	"github.com/MicronGit/Summoner-Analysis/internal/riot/riottest"
+	"github.com/MicronGit/Summoner-Analysis/internal/store"
+)
+
+const fixtureDir = "../../testdata/fakeriot"
+
+// フィクスチャの試合をすべて保存したキャッシュ
+func newFixtureStore(t *testing.T) *store.FileStore {
+	t.Helper()
+
+	matchStore, err := store.NewFileStore(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(fixtureDir, "matches", "*.json"))
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		var match riot.MatchDetail
+		if err := json.Unmarshal(data, &match); err != nil {
+			t.Fatalf("Unmarshal %s: %v", file, err)
+		}
+		if err := matchStore.PutMatch(&match); err != nil {
+			t.Fatalf("PutMatch: %v", err)
+		}
+	}
+
+	return matchStore
+}
+
+// アジアのアプリ全体の制限（100 requests/2min）のうち used 件を使用済みにする
+func useAppBudget(client *riot.Client, used int) {
+	header := http.Header{}
+	header.Set("X-App-Rate-Limit", "20:1,100:120")
+	header.Set("X-App-Rate-Limit-Count", "0:1,"+strconv.Itoa(used)+":120")
+	client.RateLimiter.Update(riot.RegionAsia, riot.MethodMatchDetail, header)
+}
+
+func TestHandleLiveGame(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		used       int  // 事前に使用済みのリクエスト数
+		cached     bool // フィクスチャの試合をキャッシュ済みにする
+		wantStatus int
+	}{
+		// 10人 × (マッチID 1 + 詳細 5) でも開発者キーの制限に収まる
+		{name: "既定の設定", wantStatus: http.StatusOK},
+		{name: "マッチIDの取得前に残りが足りない", used: 95, wantStatus: http.StatusTooManyRequests},
+		// アカウント 1 + マッチID 10 を引くと残り1
+		{name: "試合の詳細の取得前に残りが足りない", used: 88, wantStatus: http.StatusTooManyRequests},
+		{name: "キャッシュ済みの試合は数えない", used: 88, cached: true, wantStatus: http.StatusOK},
+		{name: "count の指定", query: "?count=20", wantStatus: http.StatusOK},
+		{name: "不正な count", query: "?count=0", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, err := riottest.NewServer(riottest.Config{FixtureDir: fixtureDir})
+			if err != nil {
+				t.Fatalf("NewServer: %v", err)
+			}
+			ts := httptest.NewServer(fake)
+			t.Cleanup(ts.Close)
+
+			server := &Server{client: riot.NewClient("test-key", riot.PlatformJP1, riot.WithBaseURL(ts.URL))}
+			var matchStore *store.FileStore
+			if tt.cached {
+				matchStore = newFixtureStore(t)
+				server.client.MatchStore = matchStore
+				server.client.HistoryStore = matchStore
+			}
+			if tt.used > 0 {
+				useAppBudget(server.client, tt.used)
+			}
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/live/{gameName}/{tagLine}", server.handleLiveGame)
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest("GET", "/api/live/FakePlayer/JP1"+tt.query, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Success bool                    `json:"success"`
+				Data    analysis.LiveGameReport `json:"data"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if len(resp.Data.Participants) != 10 {
+				t.Fatalf("participants = %d, want 10", len(resp.Data.Participants))
+			}
+			for _, p := range resp.Data.Participants {
+				if p.Error != "" {
+					t.Errorf("%s: %s", p.RiotID, p.Error)
+				}
+
+				// 参加者の直近の試合で保存済みの履歴を上書きしない
+				if matchStore == nil {
+					continue
+				}
+				if _, ok, err := matchStore.GetPlayerHistory(p.PUUID, "all"); err != nil || ok {
+					t.Errorf("%s の履歴が保存された (err: %v)", p.RiotID, err)
+				}
+			}
+		})
+	}
+}
@@ -3,21 +3,41 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/MicronGit/Summoner-Analysis/internal/analysis"
 	"github.com/MicronGit/Summoner-Analysis/internal/config"
 	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+	"github.com/MicronGit/Summoner-Analysis/internal/staticdata"
+	"github.com/MicronGit/Summoner-Analysis/internal/store"
 )
 
+// 1回の分析で取得できる最大試合数（100件を超える分はページングで取得）
+const maxMatchCount = 300
+
+// 静的データのディレクトリ（Data Dragon の画像）を配信するURL
+const staticDataURL = "/api/static/"
+
 type APIRequest struct {
 	GameName   string `json:"gameName"`
 	TagLine    string `json:"tagLine"`
-	Region     string `json:"region"`
+	Region     string `json:"region"`             // asia などのリージョン、または jp1 などのプラットフォーム
+	Platform   string `json:"platform,omitempty"` // 指定した場合は Region より優先
 	GameType   string `json:"gameType"`
 	MatchCount int    `json:"matchCount"`
+
+	// 任意の絞り込み条件
+	Champions []string `json:"champions,omitempty"`
+	Positions []string `json:"positions,omitempty"`
+
+	// タイムラインを取得してレーン戦分析を行う（試合数分のリクエストが追加される）
+	IncludeTimelines bool `json:"includeTimelines,omitempty"`
 }
 
 type APIResponse struct {
@@ -27,8 +47,10 @@ type APIResponse struct {
 }
 
 type Server struct {
-	cfg    *config.Config
-	client *riot.Client
+	cfg        *config.Config
+	client     *riot.Client
+	matchStore *store.FileStore
+	staticData *staticdata.Store // Data Dragon を取り込んでいない場合は nil
 }
 
 func NewServer() *Server {
@@ -37,10 +59,56 @@ func NewServer() *Server {
 		log.Fatal("RIOT_API_KEY が設定されていません")
 	}
 
-	return &Server{
+	clientOpts := []riot.Option{
+		riot.WithUserAgent("Summoner-Analysis"),
+		riot.WithProgress(riot.ProgressFunc(logProgress)),
+	}
+	if cfg.RiotBaseURL != "" {
+		clientOpts = append(clientOpts, riot.WithBaseURL(cfg.RiotBaseURL))
+	}
+	if cfg.Platform != "" {
+		clientOpts = append(clientOpts, riot.WithPlatform(cfg.Platform))
+	}
+
+	server := &Server{
 		cfg:    cfg,
-		client: riot.NewClient(cfg.RiotAPIKey, cfg.Region),
+		client: riot.NewClient(cfg.RiotAPIKey, cfg.Region, clientOpts...),
 	}
+	server.client.FetchWorkers = cfg.FetchWorkers
+
+	if _, err := server.client.Routing(); err != nil {
+		log.Fatalf("REGION / PLATFORM の設定が不正です: %v", err)
+	}
+
+	// マッチキャッシュ設定
+	if cfg.StoreDir != "" {
+		matchStore, err := store.NewFileStore(cfg.StoreDir, cfg.StoreMaxMatches)
+		if err != nil {
+			log.Fatalf("マッチキャッシュ初期化エラー: %v", err)
+		}
+		server.matchStore = matchStore
+		server.client.MatchStore = matchStore
+		server.client.HistoryStore = matchStore
+		server.client.TimelineStore = matchStore
+		server.client.LeagueHistoryStore = matchStore
+	}
+
+	// 静的データ（取り込んでいない場合は試合履歴から分かる名前のみ使用）
+	if cfg.StaticDataDir != "" {
+		staticData, err := staticdata.Open(cfg.StaticDataDir, cfg.StaticDataLocale)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			log.Printf("Static data load error: %v", err)
+		case len(staticData.Versions()) == 0:
+			log.Printf("No Data Dragon snapshot in %s (%s)", cfg.StaticDataDir, cfg.StaticDataLocale)
+		default:
+			log.Printf("Static data loaded: %v", staticData.Versions())
+			server.staticData = staticData
+		}
+	}
+
+	return server
 }
 
 func (s *Server) enableCORS(w http.ResponseWriter) {
@@ -68,396 +136,179 @@ func (s *Server) handleAnalyze(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// バリデーション
-	if req.GameName == "" || req.TagLine == "" {
-		s.sendError(w, "GameName and TagLine are required", http.StatusBadRequest)
+	client, opts, err := s.prepareAnalysis(&req)
+	if err != nil {
+		s.sendError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	if req.MatchCount <= 0 || req.MatchCount > 100 {
-		req.MatchCount = 50
-	}
-
-	// クライアントのリージョンを更新
-	if req.Region != "" {
-		s.client.Region = req.Region
-	}
-
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
 	defer cancel()
 
-	log.Printf("Starting analysis for %s#%s (region: %s, gameType: %s, matches: %d)",
-		req.GameName, req.TagLine, req.Region, req.GameType, req.MatchCount)
-
 	// アカウント情報取得
-	account, err := s.client.GetAccountByRiotID(req.GameName, req.TagLine)
+	account, err := client.GetAccountByRiotID(req.GameName, req.TagLine)
 	if err != nil {
 		log.Printf("Account fetch error: %v", err)
-		s.sendError(w, fmt.Sprintf("アカウント取得エラー: %v", err), http.StatusNotFound)
+		s.sendRiotError(w, "アカウント取得エラー", err)
 		return
 	}
 
-	// マッチ分析実行
-	var analysis *riot.PlayerMatchSummary
-
-	switch req.GameType {
-	case "ranked":
-		analysis, err = s.client.GetPlayerRankedAnalysisWithContext(ctx, account, req.MatchCount)
-	case "normal":
-		analysis, err = s.client.GetPlayerNormalAnalysisWithContext(ctx, account, req.MatchCount)
-	case "aram":
-		analysis, err = s.client.GetPlayerARAMAnalysisWithContext(ctx, account, req.MatchCount)
-	case "all":
-		analysis, err = s.client.GetPlayerAllAnalysisWithContext(ctx, account, req.MatchCount)
-	default:
-		analysis, err = s.client.GetPlayerRankedAnalysisWithContext(ctx, account, req.MatchCount)
-	}
-
+	stats, err := s.analyze(ctx, client, account, opts)
 	if err != nil {
 		log.Printf("Analysis error: %v", err)
-		s.sendError(w, fmt.Sprintf("分析エラー: %v", err), http.StatusInternalServerError)
+		s.sendRiotError(w, "分析エラー", err)
 		return
 	}
 
-	// 統計計算（簡略版）
-	stats := s.calculateStats(analysis)
-
-	log.Printf("Analysis completed for %s#%s: %d matches", req.GameName, req.TagLine, analysis.TotalMatches)
-
 	s.sendSuccess(w, stats)
 }
 
-func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
-	s.enableCORS(w)
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
-}
-
-func (s *Server) sendError(w http.ResponseWriter, message string, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(APIResponse{
-		Success: false,
-		Error:   message,
-	})
-}
-
-func (s *Server) sendSuccess(w http.ResponseWriter, data any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(APIResponse{
-		Success: true,
-		Data:    data,
-	})
-}
-
-type ChampionStat struct {
-	ChampionName string  `json:"championName"`
-	GamesPlayed  int     `json:"gamesPlayed"`
-	Wins         int     `json:"wins"`
-	TotalKills   int     `json:"totalKills"`
-	TotalDeaths  int     `json:"totalDeaths"`
-	TotalAssists int     `json:"totalAssists"`
-	WinRate      float64 `json:"winRate"`
-	AverageKDA   struct {
-		Kills    float64 `json:"kills"`
-		Deaths   float64 `json:"deaths"`
-		Assists  float64 `json:"assists"`
-		KDARatio float64 `json:"kdaRatio"`
-	} `json:"averageKDA"`
-}
-
-type RecentFormData struct {
-	WinRate    float64 `json:"winRate"`
-	AverageKDA struct {
-		Kills    float64 `json:"kills"`
-		Deaths   float64 `json:"deaths"`
-		Assists  float64 `json:"assists"`
-		KDARatio float64 `json:"kdaRatio"`
-	} `json:"averageKDA"`
-}
-
-func (s *Server) calculateStats(analysis *riot.PlayerMatchSummary) map[string]any {
-	if len(analysis.MatchHistory) == 0 {
-		return map[string]any{
-			"playerInfo": map[string]string{
-				"gameName": analysis.Account.SummonerName,
-				"tagLine":  analysis.Account.TagLine,
-			},
-			"totalMatches": 0,
-			"winRate":      0.0,
-			"averageKDA": map[string]float64{
-				"kills":    0,
-				"deaths":   0,
-				"assists":  0,
-				"kdaRatio": 0,
-			},
-		}
+// リクエストの検証・既定値の設定を行い、分析条件とリクエストごとの接続先を返す
+// 返すエラーはそのまま 400 のメッセージとして使う
+func (s *Server) prepareAnalysis(req *APIRequest) (*riot.Client, riot.AnalysisOptions, error) {
+	// バリデーション
+	if req.GameName == "" || req.TagLine == "" {
+		return nil, riot.AnalysisOptions{}, errors.New("GameName and TagLine are required")
 	}
 
-	// プレイヤーデータの抽出
-	var playerMatches []riot.Participant
-	for _, match := range analysis.MatchHistory {
-		for _, participant := range match.Info.Participants {
-			if participant.PUUID == analysis.Account.PUUID {
-				playerMatches = append(playerMatches, participant)
-				break
-			}
-		}
+	if req.MatchCount <= 0 || req.MatchCount > maxMatchCount {
+		req.MatchCount = 50
 	}
 
-	if len(playerMatches) == 0 {
-		return map[string]any{
-			"playerInfo": map[string]string{
-				"gameName": analysis.Account.SummonerName,
-				"tagLine":  analysis.Account.TagLine,
-			},
-			"totalMatches": 0,
-			"winRate":      0.0,
-		}
+	if req.GameType == "" {
+		req.GameType = "ranked"
 	}
 
-	// 基本統計計算
-	totalStats := s.calculateBasicStats(playerMatches)
-
-	// チャンピオン統計
-	championStats := s.calculateChampionStats(playerMatches)
-
-	// ポジション統計
-	positionStats := s.calculatePositionStats(playerMatches)
-
-	// 直近フォーム
-	recentForm := s.calculateRecentForm(playerMatches)
-
-	// CS/分計算
-	avgCSPerMin := s.calculateCSPerMin(analysis.MatchHistory, playerMatches)
-
-	return map[string]any{
-		"playerInfo": map[string]string{
-			"gameName": analysis.Account.SummonerName,
-			"tagLine":  analysis.Account.TagLine,
-		},
-		"generatedAt":  analysis.GeneratedAt.Format(time.RFC3339),
-		"matchType":    analysis.MatchType,
-		"totalMatches": len(playerMatches),
-		"winRate":      totalStats.WinRate,
-		"averageKDA": map[string]float64{
-			"kills":    totalStats.AvgKills,
-			"deaths":   totalStats.AvgDeaths,
-			"assists":  totalStats.AvgAssists,
-			"kdaRatio": totalStats.KDARatio,
-		},
-		"rankPerformance": map[string]float64{
-			"averageVisionScore": totalStats.AvgVisionScore,
-			"averageGoldEarned":  totalStats.AvgGoldEarned,
-			"averageCSPerMin":    avgCSPerMin,
-		},
-		"mostPlayedChampions": championStats,
-		"positionStats":       positionStats,
-		"recentForm":          recentForm,
+	opts, ok := riot.AnalysisOptionsForGameType(req.GameType, req.MatchCount)
+	if !ok {
+		return nil, riot.AnalysisOptions{}, fmt.Errorf("Unknown gameType: %s", req.GameType)
 	}
-}
-
-type BasicStats struct {
-	WinRate          float64
-	AvgKills         float64
-	AvgDeaths        float64
-	AvgAssists       float64
-	KDARatio         float64
-	AvgVisionScore   float64
-	AvgGoldEarned    float64
-}
-
-func (s *Server) calculateBasicStats(playerMatches []riot.Participant) BasicStats {
-	var totalKills, totalDeaths, totalAssists int
-	var totalVisionScore, totalGoldEarned int
-	var wins int
+	opts.Filter.Champions = req.Champions
+	opts.Filter.Positions = req.Positions
+	opts.IncludeTimelines = req.IncludeTimelines
 
-	for _, match := range playerMatches {
-		totalKills += match.Kills
-		totalDeaths += match.Deaths
-		totalAssists += match.Assists
-		totalVisionScore += match.VisionScore
-		totalGoldEarned += match.GoldEarned
-
-		if match.Win {
-			wins++
-		}
+	// リクエストごとの接続先（共有のクライアントは変更しない）
+	client, err := s.client.ForRouting(req.Region, req.Platform)
+	if err != nil {
+		return nil, riot.AnalysisOptions{}, err
 	}
 
-	matchCount := float64(len(playerMatches))
-	avgKills := float64(totalKills) / matchCount
-	avgDeaths := float64(totalDeaths) / matchCount
-	avgAssists := float64(totalAssists) / matchCount
-
-	var kdaRatio float64
-	if avgDeaths > 0 {
-		kdaRatio = (avgKills + avgAssists) / avgDeaths
-	} else {
-		kdaRatio = avgKills + avgAssists
-	}
+	routing, _ := client.Routing()
+	log.Printf("Starting analysis for %s#%s (platform: %s, region: %s, gameType: %s, matches: %d)",
+		req.GameName, req.TagLine, routing.Platform, routing.Region, req.GameType, req.MatchCount)
 
-	return BasicStats{
-		WinRate:          float64(wins) / matchCount * 100,
-		AvgKills:         avgKills,
-		AvgDeaths:        avgDeaths,
-		AvgAssists:       avgAssists,
-		KDARatio:         kdaRatio,
-		AvgVisionScore:   float64(totalVisionScore) / matchCount,
-		AvgGoldEarned:    float64(totalGoldEarned) / matchCount,
-	}
+	return client, opts, nil
 }
 
-func (s *Server) calculateChampionStats(playerMatches []riot.Participant) []ChampionStat {
-	championData := make(map[string]*ChampionStat)
-
-	for _, match := range playerMatches {
-		champName := match.ChampionName
-		if _, exists := championData[champName]; !exists {
-			championData[champName] = &ChampionStat{
-				ChampionName: champName,
-			}
-		}
-
-		champ := championData[champName]
-		champ.GamesPlayed++
-		champ.TotalKills += match.Kills
-		champ.TotalDeaths += match.Deaths
-		champ.TotalAssists += match.Assists
-
-		if match.Win {
-			champ.Wins++
-		}
-	}
-
-	// 統計計算
-	var championStats []ChampionStat
-	for _, champ := range championData {
-		games := float64(champ.GamesPlayed)
-		champ.WinRate = float64(champ.Wins) / games * 100
-		champ.AverageKDA.Kills = float64(champ.TotalKills) / games
-		champ.AverageKDA.Deaths = float64(champ.TotalDeaths) / games
-		champ.AverageKDA.Assists = float64(champ.TotalAssists) / games
-
-		if champ.AverageKDA.Deaths > 0 {
-			champ.AverageKDA.KDARatio = (champ.AverageKDA.Kills + champ.AverageKDA.Assists) / champ.AverageKDA.Deaths
-		} else {
-			champ.AverageKDA.KDARatio = champ.AverageKDA.Kills + champ.AverageKDA.Assists
-		}
-
-		championStats = append(championStats, *champ)
-	}
-
-	// ゲーム数でソート（多い順）
-	for i := 0; i < len(championStats)-1; i++ {
-		for j := i + 1; j < len(championStats); j++ {
-			if championStats[i].GamesPlayed < championStats[j].GamesPlayed {
-				championStats[i], championStats[j] = championStats[j], championStats[i]
-			}
-		}
+// マッチ分析を実行し、レスポンスの統計データを作成
+func (s *Server) analyze(ctx context.Context, client *riot.Client, account *riot.Account, opts riot.AnalysisOptions) (*analysis.PlayerStats, error) {
+	summary, err := client.GetPlayerAnalysis(ctx, account, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	return championStats
-}
+	stats := analysis.CalculatePlayerStats(summary)
+	s.resolveMatchAssets(stats, summary)
 
-func (s *Server) calculatePositionStats(playerMatches []riot.Participant) map[string]int {
-	positionStats := make(map[string]int)
+	log.Printf("Analysis completed for %s#%s: %d matches", account.SummonerName, account.TagLine, summary.TotalMatches)
 
-	for _, match := range playerMatches {
-		position := match.TeamPosition
-		if position != "" {
-			positionStats[position]++
-		}
+	if s.matchStore != nil {
+		cacheStats := s.matchStore.Stats()
+		log.Printf("Match cache: %d hits, %d misses, %d entries", cacheStats.Hits, cacheStats.Misses, cacheStats.Entries)
 	}
 
-	return positionStats
+	return stats, nil
 }
 
-func (s *Server) calculateRecentForm(playerMatches []riot.Participant) map[string]RecentFormData {
-	// 最新順にソート（既に最新順になっているはず）
-	last10 := playerMatches
-	if len(playerMatches) > 10 {
-		last10 = playerMatches[:10]
-	}
-
-	last5 := playerMatches
-	if len(playerMatches) > 5 {
-		last5 = playerMatches[:5]
+// 試合ごとのバン・装備・ルーン・サモナースペルを試合のバージョンの静的データで変換（取り込んでいない場合は省略）
+func (s *Server) resolveMatchAssets(stats *analysis.PlayerStats, summary *riot.PlayerMatchSummary) {
+	if s.staticData == nil {
+		return
 	}
 
-	return map[string]RecentFormData{
-		"last10Games": s.calculateFormData(last10),
-		"last5Games":  s.calculateFormData(last5),
+	assets, err := analysis.ResolveMatchAssets(summary, s.staticData, staticDataURL)
+	if err != nil {
+		log.Printf("Static data load error: %v", err)
+		return
 	}
+	stats.Matches = assets
 }
 
-func (s *Server) calculateFormData(matches []riot.Participant) RecentFormData {
-	if len(matches) == 0 {
-		return RecentFormData{}
-	}
-
-	var totalKills, totalDeaths, totalAssists int
-	var wins int
-
-	for _, match := range matches {
-		totalKills += match.Kills
-		totalDeaths += match.Deaths
-		totalAssists += match.Assists
-
-		if match.Win {
-			wins++
-		}
-	}
-
-	matchCount := float64(len(matches))
-	avgKills := float64(totalKills) / matchCount
-	avgDeaths := float64(totalDeaths) / matchCount
-	avgAssists := float64(totalAssists) / matchCount
-
-	var kdaRatio float64
-	if avgDeaths > 0 {
-		kdaRatio = (avgKills + avgAssists) / avgDeaths
-	} else {
-		kdaRatio = avgKills + avgAssists
-	}
-
-	form := RecentFormData{
-		WinRate: float64(wins) / matchCount * 100,
-	}
-	form.AverageKDA.Kills = avgKills
-	form.AverageKDA.Deaths = avgDeaths
-	form.AverageKDA.Assists = avgAssists
-	form.AverageKDA.KDARatio = kdaRatio
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	s.enableCORS(w)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"status":     "ok",
+		"rateLimits": s.client.RateLimiter.GetStatus(),
+	})
+}
 
-	return form
+func (s *Server) sendError(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(APIResponse{
+		Success: false,
+		Error:   message,
+	})
 }
 
-func (s *Server) calculateCSPerMin(matches []riot.MatchDetail, playerMatches []riot.Participant) float64 {
-	if len(matches) != len(playerMatches) {
-		return 0
+// Riot API のエラーを対応する HTTP ステータスコードで返す
+func (s *Server) sendRiotError(w http.ResponseWriter, prefix string, err error) {
+	var apiErr *riot.RiotAPIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(apiErr.RetryAfter.Seconds())))
 	}
 
-	var totalCS int
-	var totalMinutes float64
+	s.sendError(w, fmt.Sprintf("%s: %v", prefix, err), statusCodeForError(err))
+}
 
-	for i, match := range matches {
-		if i < len(playerMatches) {
-			totalCS += playerMatches[i].TotalMinionsKilled + playerMatches[i].NeutralMinionsKilled
-			totalMinutes += float64(match.Info.GameDuration) / 60.0
-		}
+func statusCodeForError(err error) int {
+	switch {
+	case errors.Is(err, riot.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, riot.ErrUnauthorized), errors.Is(err, riot.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, riot.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, riot.ErrBadRequest), errors.Is(err, riot.ErrUnknownRouting):
+		return http.StatusBadRequest
+	case errors.Is(err, riot.ErrServer):
+		return http.StatusBadGateway
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
 	}
+}
 
-	if totalMinutes > 0 {
-		return float64(totalCS) / totalMinutes
-	}
-	return 0
+func (s *Server) sendSuccess(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(APIResponse{
+		Success: true,
+		Data:    data,
+	})
 }
 
 func main() {
 	server := NewServer()
 
 	http.HandleFunc("/api/analyze", server.handleAnalyze)
+	http.HandleFunc("/api/analyze/stream", server.handleAnalyzeStream)
 	http.HandleFunc("/api/health", server.handleHealth)
+	http.HandleFunc("/api/live/{gameName}/{tagLine}", server.handleLiveGame)
+
+	// LP 推移の記録対象がある場合は定期的にランクを取得
+	if len(server.cfg.TrackedPlayers) > 0 && server.client.LeagueHistoryStore != nil && server.cfg.LeaguePollInterval > 0 {
+		log.Printf("Tracking league snapshots for %d players every %v", len(server.cfg.TrackedPlayers), server.cfg.LeaguePollInterval)
+		go server.pollLeagueSnapshots(context.Background(), server.cfg.TrackedPlayers, server.cfg.LeaguePollInterval)
+	}
+
+	// Data Dragon の画像（分析結果のアイコンのURLから参照）
+	if server.staticData != nil {
+		http.Handle(staticDataURL, http.StripPrefix(staticDataURL, http.FileServer(http.Dir(server.staticData.Dir()))))
+	}
 
 	// 静的ファイル配信（本番用）
 	fs := http.FileServer(http.Dir("./dist"))
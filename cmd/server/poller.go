@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+// ランクを定期的に記録するプレイヤー
+type trackedPlayer struct {
+	riotID            string // "gameName#tagLine"（ログ用）
+	gameName, tagLine string
+	platform          string       // 省略した場合はサーバーの既定のプラットフォーム
+	client            *riot.Client // platform に接続するクライアント
+	puuid             string       // 初回の取得時に設定
+}
+
+// TRACKED_PLAYERS の "gameName#tagLine" または "gameName#tagLine@platform" を解析（不正な値は除外）
+func (s *Server) parseTrackedPlayers(players []string) []*trackedPlayer {
+	var tracked []*trackedPlayer
+	for _, player := range players {
+		riotID, platform, _ := strings.Cut(player, "@")
+		gameName, tagLine, ok := strings.Cut(riotID, "#")
+		if !ok || gameName == "" || tagLine == "" {
+			log.Printf("League poll: invalid Riot ID %q (expected gameName#tagLine or gameName#tagLine@platform)", player)
+			continue
+		}
+
+		client, err := s.client.ForRouting("", platform)
+		if err != nil {
+			log.Printf("League poll: invalid platform for %s: %v", player, err)
+			continue
+		}
+		routing, err := client.Routing()
+		if err != nil {
+			log.Printf("League poll: invalid routing for %s: %v", player, err)
+			continue
+		}
+
+		tracked = append(tracked, &trackedPlayer{
+			riotID:   riotID,
+			gameName: gameName,
+			tagLine:  tagLine,
+			platform: routing.Platform,
+			client:   client,
+		})
+	}
+	return tracked
+}
+
+// 追跡対象プレイヤーのランクを定期的に記録（分析時以外の LP 変化も追えるようにする）
+// プレイヤーごとに指定したプラットフォームから取得する
+func (s *Server) pollLeagueSnapshots(ctx context.Context, players []string, interval time.Duration) {
+	tracked := s.parseTrackedPlayers(players)
+
+	poll := func() {
+		for _, player := range tracked {
+			if player.puuid == "" {
+				account, err := player.client.GetAccountByRiotID(player.gameName, player.tagLine)
+				if err != nil {
+					log.Printf("League poll: account fetch error for %s: %v", player.riotID, err)
+					continue
+				}
+				player.puuid = account.PUUID
+			}
+
+			if _, err := player.client.RecordLeagueSnapshotWithContext(ctx, player.puuid); err != nil {
+				log.Printf("League poll: league fetch error for %s (%s): %v", player.riotID, player.platform, err)
+			}
+		}
+	}
+
+	poll()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			poll()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+// 進捗イベントをサーバーログに出力（1件ごとの進捗は出力しない）
+func logProgress(event riot.ProgressEvent) {
+	switch event.Type {
+	case riot.ProgressPhaseDone:
+		if event.Total > 0 {
+			log.Printf("%s: %d/%d done in %v (%d skipped)", event.Phase, event.Fetched, event.Total,
+				event.Elapsed.Round(time.Millisecond), event.Skipped)
+		}
+	case riot.ProgressSkipped:
+		log.Printf("%s: skipped %s: %v", event.Phase, event.MatchID, event.Err)
+	case riot.ProgressRateLimitWait:
+		log.Printf("Rate limited on %s (%s): waiting %v", event.Routing, event.Method, event.Wait.Round(time.Millisecond))
+	case riot.ProgressRetry:
+		log.Printf("Retrying %s (%s) after %v: %s %v", event.Routing, event.Method, event.Wait, event.Message, event.Err)
+	case riot.ProgressWarning:
+		log.Printf("Warning (%s): %s: %v", event.Phase, event.Message, event.Err)
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/analysis"
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+// Server-Sent Events のイベント名
+const (
+	streamEventProgress = "progress" // riot.ProgressEvent
+	streamEventPartial  = "partial"  // 取得済みの試合の途中集計
+	streamEventResult   = "result"   // /api/analyze と同じレスポンス
+	streamEventError    = "error"
+)
+
+// レート制限の待機中もプロキシに切断されないよう、一定間隔でコメントを送る
+const streamKeepAliveInterval = 15 * time.Second
+
+// 取得済みの試合のうち分析対象の試合の途中集計
+type PartialStats struct {
+	Matches    int               `json:"matches"`
+	Wins       int               `json:"wins"`
+	WinRate    float64           `json:"winRate"`
+	AverageKDA analysis.KDAStats `json:"averageKDA"`
+}
+
+// ストリームで送るエラー（status は /api/analyze で返す場合の HTTP ステータスコード）
+type streamError struct {
+	Success    bool   `json:"success"`
+	Error      string `json:"error"`
+	Status     int    `json:"status"`
+	RetryAfter int    `json:"retryAfter,omitempty"` // 秒
+}
+
+// 分析の進捗を Server-Sent Events で配信し、最後に結果を送る
+// GET /api/analyze/stream?gameName=...&tagLine=...&platform=jp1&gameType=ranked&matchCount=50
+//
+// 開始後のエラーは HTTP ステータスではなく error イベントで通知する
+func (s *Server) handleAnalyzeStream(w http.ResponseWriter, r *http.Request) {
+	s.enableCORS(w)
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	stream, ok := newEventStream(w)
+	if !ok {
+		s.sendError(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	req := apiRequestFromQuery(r.URL.Query())
+	client, opts, err := s.prepareAnalysis(&req)
+	if err != nil {
+		stream.sendError(err.Error(), http.StatusBadRequest, 0)
+		return
+	}
+
+	// クライアントが切断した場合は分析も中断する
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Minute)
+	defer cancel()
+
+	account, err := client.GetAccountByRiotID(req.GameName, req.TagLine)
+	if err != nil {
+		log.Printf("Account fetch error: %v", err)
+		stream.sendRiotError("アカウント取得エラー", err)
+		return
+	}
+
+	// 進捗はこのリクエストにだけ配信する（サーバーログにも出力）
+	events := make(chan riot.ProgressEvent, 64)
+	reporter := riot.ProgressFunc(func(event riot.ProgressEvent) {
+		logProgress(event)
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	})
+
+	type analysisResult struct {
+		stats *analysis.PlayerStats
+		err   error
+	}
+	done := make(chan analysisResult, 1)
+
+	go func() {
+		stats, err := s.analyze(riot.ContextWithProgress(ctx, reporter), client, account, opts)
+		done <- analysisResult{stats: stats, err: err}
+	}()
+
+	partial := &partialStats{puuid: account.PUUID, filter: opts.Filter}
+	keepAlive := time.NewTicker(streamKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			stream.sendProgress(event, partial)
+
+		case result := <-done:
+			// 分析が終わるまでに通知された進捗を先に送る
+			for len(events) > 0 {
+				stream.sendProgress(<-events, partial)
+			}
+
+			if result.err != nil {
+				log.Printf("Analysis error: %v", result.err)
+				stream.sendRiotError("分析エラー", result.err)
+				return
+			}
+
+			stream.send(streamEventResult, APIResponse{Success: true, Data: result.stats})
+			return
+
+		case <-keepAlive.C:
+			stream.keepAlive()
+		}
+	}
+}
+
+// クエリパラメータから分析リクエストを作成（champions・positions はカンマ区切り）
+func apiRequestFromQuery(query url.Values) APIRequest {
+	req := APIRequest{
+		GameName:  query.Get("gameName"),
+		TagLine:   query.Get("tagLine"),
+		Region:    query.Get("region"),
+		Platform:  query.Get("platform"),
+		GameType:  query.Get("gameType"),
+		Champions: splitQueryList(query["champions"]),
+		Positions: splitQueryList(query["positions"]),
+	}
+
+	// 不正な値は未指定として扱い、既定値を使う
+	req.MatchCount, _ = strconv.Atoi(query.Get("matchCount"))
+	req.IncludeTimelines, _ = strconv.ParseBool(query.Get("includeTimelines"))
+
+	return req
+}
+
+// ?champions=Ahri,Lux と ?champions=Ahri&champions=Lux のどちらも受け付ける
+func splitQueryList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+// 取得済みのマッチ詳細から途中集計を計算
+type partialStats struct {
+	puuid  string
+	filter riot.MatchFilter
+
+	matches, wins          int
+	kills, deaths, assists int
+}
+
+// 分析対象の試合であれば集計に加える（加えた場合は true）
+func (p *partialStats) add(match *riot.MatchDetail) bool {
+	if !p.filter.Match(match, p.puuid) {
+		return false
+	}
+
+	for _, participant := range match.Info.Participants {
+		if participant.PUUID != p.puuid {
+			continue
+		}
+
+		p.matches++
+		p.kills += participant.Kills
+		p.deaths += participant.Deaths
+		p.assists += participant.Assists
+		if participant.Win {
+			p.wins++
+		}
+		return true
+	}
+	return false
+}
+
+func (p *partialStats) stats() PartialStats {
+	stats := PartialStats{
+		Matches:    p.matches,
+		Wins:       p.wins,
+		AverageKDA: analysis.AverageKDA(p.kills, p.deaths, p.assists, p.matches),
+	}
+	if p.matches > 0 {
+		stats.WinRate = float64(p.wins) / float64(p.matches) * 100
+	}
+	return stats
+}
+
+// text/event-stream のレスポンス
+type eventStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+// ヘッダーを送信してストリームを開始（ResponseWriter が Flush に対応していない場合は ok=false）
+func newEventStream(w http.ResponseWriter) (*eventStream, bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, false
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx のバッファリングを無効化
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	return &eventStream{w: w, flusher: flusher}, true
+}
+
+// イベントを送信（クライアントが切断している場合の書き込みエラーは無視する）
+func (s *eventStream) send(event string, data any) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Stream event encode error: %v", err)
+		return
+	}
+
+	fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, payload)
+	s.flusher.Flush()
+}
+
+// 進捗を送信し、マッチ詳細を取得した場合は途中集計も送る
+func (s *eventStream) sendProgress(event riot.ProgressEvent, partial *partialStats) {
+	s.send(streamEventProgress, event)
+
+	if event.Type == riot.ProgressAdvance && event.Match != nil && partial.add(event.Match) {
+		s.send(streamEventPartial, partial.stats())
+	}
+}
+
+func (s *eventStream) sendError(message string, status int, retryAfter time.Duration) {
+	s.send(streamEventError, streamError{
+		Success:    false,
+		Error:      message,
+		Status:     status,
+		RetryAfter: int(retryAfter.Seconds()),
+	})
+}
+
+// Riot API のエラーを sendRiotError と同じステータスコードで通知
+func (s *eventStream) sendRiotError(prefix string, err error) {
+	var retryAfter time.Duration
+	var apiErr *riot.RiotAPIError
+	if errors.As(err, &apiErr) {
+		retryAfter = apiErr.RetryAfter
+	}
+
+	s.sendError(fmt.Sprintf("%s: %v", prefix, err), statusCodeForError(err), retryAfter)
+}
+
+func (s *eventStream) keepAlive() {
+	fmt.Fprint(s.w, ": keep-alive\n\n")
+	s.flusher.Flush()
+}
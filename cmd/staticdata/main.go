@@ -0,0 +1,130 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/config"
+	"github.com/MicronGit/Summoner-Analysis/internal/staticdata"
+)
+
+// Data Dragon の静的データを管理するコマンド
+// 例: go run ./cmd/staticdata import dragontail-14.10.1.tgz queues.json maps.json
+//
+//	go run ./cmd/staticdata list
+//	go run ./cmd/staticdata show -game-version 14.10.584.8247
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+
+	switch os.Args[1] {
+	case "import":
+		runImport(cfg, os.Args[2:])
+	case "list":
+		runList(cfg, os.Args[2:])
+	case "show":
+		runShow(cfg, os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `使い方:
+  staticdata import [-dir DIR] [-locales ja_JP,en_US] [-images] <dragontail-*.tgz | queues.json | maps.json>...
+  staticdata list [-dir DIR]
+  staticdata show [-dir DIR] [-game-version 14.10.584.8247]`)
+}
+
+// アーカイブ・静的データファイルを取り込む
+func runImport(cfg *config.Config, args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	dir := fs.String("dir", cfg.StaticDataDir, "取り込み先ディレクトリ")
+	locales := fs.String("locales", cfg.StaticDataLocale, "取り込む言語（カンマ区切り、空ですべて）")
+	images := fs.Bool("images", false, "画像も取り込む")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 || *dir == "" {
+		usage()
+		os.Exit(2)
+	}
+
+	opts := staticdata.ImportOptions{Images: *images}
+	for _, locale := range strings.Split(*locales, ",") {
+		if locale = strings.TrimSpace(locale); locale != "" {
+			opts.Locales = append(opts.Locales, locale)
+		}
+	}
+
+	for _, file := range fs.Args() {
+		switch filepath.Ext(file) {
+		case ".json":
+			if err := staticdata.ImportStaticFile(file, *dir); err != nil {
+				log.Fatalf("取り込みエラー: %v", err)
+			}
+			fmt.Printf("✅ %s を取り込みました\n", filepath.Base(file))
+		default:
+			result, err := staticdata.ImportArchive(file, *dir, opts)
+			if err != nil {
+				log.Fatalf("取り込みエラー: %v", err)
+			}
+			fmt.Printf("✅ %s を取り込みました（バージョン: %s, %dファイル）\n",
+				filepath.Base(file), strings.Join(result.Versions, ", "), result.Files)
+		}
+	}
+}
+
+// 取り込み済みのバージョンを表示
+func runList(cfg *config.Config, args []string) {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	dir := fs.String("dir", cfg.StaticDataDir, "静的データのディレクトリ")
+	fs.Parse(args)
+
+	store, err := staticdata.Open(*dir, cfg.StaticDataLocale)
+	if err != nil {
+		log.Fatalf("静的データ読み込みエラー: %v", err)
+	}
+
+	versions := store.Versions()
+	if len(versions) == 0 {
+		fmt.Printf("%s（%s）のスナップショットはありません\n", *dir, cfg.StaticDataLocale)
+		return
+	}
+	for _, version := range versions {
+		fmt.Println(version)
+	}
+}
+
+// 試合のバージョンに対応するスナップショットの概要を表示
+func runShow(cfg *config.Config, args []string) {
+	fs := flag.NewFlagSet("show", flag.ExitOnError)
+	dir := fs.String("dir", cfg.StaticDataDir, "静的データのディレクトリ")
+	gameVersion := fs.String("game-version", "", "試合のバージョン（空の場合は最新）")
+	fs.Parse(args)
+
+	store, err := staticdata.Open(*dir, cfg.StaticDataLocale)
+	if err != nil {
+		log.Fatalf("静的データ読み込みエラー: %v", err)
+	}
+
+	snapshot, err := store.ForGameVersion(*gameVersion)
+	if err != nil {
+		log.Fatalf("静的データ読み込みエラー: %v", err)
+	}
+
+	fmt.Printf("バージョン: %s（%s）\n", snapshot.Version, snapshot.Locale)
+	fmt.Printf("チャンピオン: %d\n", len(snapshot.Champions))
+	fmt.Printf("アイテム: %d\n", len(snapshot.Items))
+	fmt.Printf("ルーン: %d（パス %d）\n", len(snapshot.Runes), len(snapshot.RuneStyles))
+	fmt.Printf("サモナースペル: %d\n", len(snapshot.SummonerSpells))
+	fmt.Printf("マップ: %d\n", len(snapshot.Maps))
+}
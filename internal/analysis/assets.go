@@ -0,0 +1,120 @@
+package analysis
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+	"github.com/MicronGit/Summoner-Analysis/internal/staticdata"
+)
+
+// 静的データで名前・アイコンに変換したID（アイコンは画像を取り込んでいない場合は存在しないURL・パス）
+type ResolvedID struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Icon string `json:"icon"`
+}
+
+// 1試合分のバン・装備・ルーン・サモナースペル（試合のバージョンに対応する静的データで変換）
+type MatchAssets struct {
+	MatchID        string       `json:"matchId"`
+	GameVersion    string       `json:"gameVersion"`
+	DataVersion    string       `json:"dataVersion"` // 使用した Data Dragon のバージョン
+	Bans           []ResolvedID `json:"bans"`        // 両チームのバン（バンなしは除く）
+	Items          []ResolvedID `json:"items"`       // アイテム0〜6（空き枠は除く）
+	Runes          []ResolvedID `json:"runes"`       // メインのパス・ルーン、サブのパス・ルーンの順
+	SummonerSpells []ResolvedID `json:"summonerSpells"`
+}
+
+// 分析対象の試合のバン・装備・ルーン・サモナースペルを試合ごとのバージョンの静的データで変換
+// アイコンは iconBase（静的データのディレクトリを配信するURL、またはディレクトリのパス）からのパスにする
+// プレイヤーが参加していない試合は含めない
+func ResolveMatchAssets(summary *riot.PlayerMatchSummary, store *staticdata.Store, iconBase string) ([]MatchAssets, error) {
+	assets := []MatchAssets{}
+
+	for i := range summary.MatchHistory {
+		match := &summary.MatchHistory[i]
+
+		player := riot.FindParticipant(match, summary.Account.PUUID)
+		if player == nil {
+			continue
+		}
+
+		snapshot, err := store.ForGameVersion(match.Info.GameVersion)
+		if err != nil {
+			return nil, fmt.Errorf("静的データ取得エラー (%s): %w", match.Metadata.MatchID, err)
+		}
+
+		assets = append(assets, matchAssets(match, player, snapshot, iconBase))
+	}
+
+	return assets, nil
+}
+
+func matchAssets(match *riot.MatchDetail, player *riot.Participant, snapshot *staticdata.Snapshot, iconBase string) MatchAssets {
+	assets := MatchAssets{
+		MatchID:        match.Metadata.MatchID,
+		GameVersion:    match.Info.GameVersion,
+		DataVersion:    snapshot.Version,
+		Bans:           []ResolvedID{},
+		Items:          []ResolvedID{},
+		Runes:          []ResolvedID{},
+		SummonerSpells: []ResolvedID{},
+	}
+
+	// バンなしは championId が -1
+	for _, team := range match.Info.Teams {
+		for _, ban := range team.Bans {
+			if ban.ChampionID <= 0 {
+				continue
+			}
+			resolved := ResolvedID{ID: ban.ChampionID, Name: snapshot.ChampionName(ban.ChampionID)}
+			if champion, ok := snapshot.Champions[ban.ChampionID]; ok {
+				resolved.Icon = path.Join(iconBase, snapshot.ImageURL(champion.Image))
+			}
+			assets.Bans = append(assets.Bans, resolved)
+		}
+	}
+
+	for _, itemID := range player.Items() {
+		if itemID == 0 {
+			continue
+		}
+		resolved := ResolvedID{ID: itemID, Name: snapshot.ItemName(itemID)}
+		if item, ok := snapshot.Items[itemID]; ok {
+			resolved.Icon = path.Join(iconBase, snapshot.ImageURL(item.Image))
+		}
+		assets.Items = append(assets.Items, resolved)
+	}
+
+	for _, style := range player.Perks.Styles {
+		assets.Runes = append(assets.Runes, resolveRune(snapshot, style.Style, iconBase))
+		for _, selection := range style.Selections {
+			assets.Runes = append(assets.Runes, resolveRune(snapshot, selection.Perk, iconBase))
+		}
+	}
+
+	for _, spellID := range []int{player.Summoner1ID, player.Summoner2ID} {
+		if spellID == 0 {
+			continue
+		}
+		resolved := ResolvedID{ID: spellID, Name: snapshot.SummonerSpellName(spellID)}
+		if spell, ok := snapshot.SummonerSpells[spellID]; ok {
+			resolved.Icon = path.Join(iconBase, snapshot.ImageURL(spell.Image))
+		}
+		assets.SummonerSpells = append(assets.SummonerSpells, resolved)
+	}
+
+	return assets
+}
+
+// ルーンまたはパスのIDを変換
+func resolveRune(snapshot *staticdata.Snapshot, runeID int, iconBase string) ResolvedID {
+	resolved := ResolvedID{ID: runeID, Name: snapshot.RuneName(runeID)}
+	if perk, ok := snapshot.Runes[runeID]; ok {
+		resolved.Icon = path.Join(iconBase, snapshot.RuneIconURL(perk.Icon))
+	} else if style, ok := snapshot.RuneStyles[runeID]; ok {
+		resolved.Icon = path.Join(iconBase, snapshot.RuneIconURL(style.Icon))
+	}
+	return resolved
+}
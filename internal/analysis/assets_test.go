@@ -0,0 +1,144 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+	"github.com/MicronGit/Summoner-Analysis/internal/staticdata"
+)
+
+// バージョンごとに名前の異なる Data Dragon のスナップショットを作成
+func writeTestSnapshot(t *testing.T, dir, version, suffix string) {
+	t.Helper()
+
+	files := map[string]string{
+		"champion.json": `{"data":{"Ahri":{"id":"Ahri","key":"103","name":"アーリ` + suffix + `","image":{"full":"Ahri.png","group":"champion"}}}}`,
+		"item.json":     `{"data":{"3340":{"name":"ワード トーテム` + suffix + `","image":{"full":"3340.png","group":"item"}}}}`,
+		"runesReforged.json": `[{"id":8100,"key":"Domination","name":"覇道` + suffix + `","icon":"perk-images/Styles/7200_Domination.png",
+			"slots":[{"runes":[{"id":8112,"key":"Electrocute","name":"電撃` + suffix + `","icon":"perk-images/Styles/Domination/Electrocute/Electrocute.png"}]}]}]`,
+		"summoner.json": `{"data":{"SummonerFlash":{"id":"SummonerFlash","key":"4","name":"フラッシュ` + suffix + `","image":{"full":"SummonerFlash.png","group":"spell"}}}}`,
+	}
+
+	dataDir := filepath.Join(dir, version, "data", "ja_JP")
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dataDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+}
+
+func assetsTestMatch(matchID, gameVersion string) riot.MatchDetail {
+	var match riot.MatchDetail
+	match.Metadata.MatchID = matchID
+	match.Info.GameVersion = gameVersion
+	match.Info.Teams = []riot.Team{
+		{TeamID: 100, Bans: []riot.Ban{{ChampionID: 103, PickTurn: 1}, {ChampionID: -1, PickTurn: 2}}},
+		{TeamID: 200, Bans: []riot.Ban{{ChampionID: 999, PickTurn: 6}}},
+	}
+	match.Info.Participants = []riot.Participant{{
+		PUUID:       fixturePUUID,
+		Item0:       3340,
+		Item6:       3340,
+		Summoner1ID: 4,
+		Summoner2ID: 14,
+		Perks: riot.MatchPerks{Styles: []riot.PerkStyle{
+			{Description: "primaryStyle", Style: 8100, Selections: []riot.PerkSelection{{Perk: 8112}}},
+		}},
+	}}
+	return match
+}
+
+func TestResolveMatchAssets(t *testing.T) {
+	dir := t.TempDir()
+	writeTestSnapshot(t, dir, "15.19.1", "（旧）")
+	writeTestSnapshot(t, dir, "15.20.1", "")
+
+	store, err := staticdata.Open(dir, "ja_JP")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		gameVersion string
+		wantData    string
+		suffix      string
+	}{
+		{name: "同じパッチ", gameVersion: "15.20.717.2831", wantData: "15.20.1"},
+		{name: "以前のパッチ", gameVersion: "15.19.715.1836", wantData: "15.19.1", suffix: "（旧）"},
+		{name: "取り込んでいないパッチはそれ以前で最新", gameVersion: "15.22.1.1", wantData: "15.20.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := &riot.PlayerMatchSummary{
+				Account:      riot.Account{PUUID: fixturePUUID},
+				MatchHistory: []riot.MatchDetail{assetsTestMatch("JP1_1", tt.gameVersion)},
+			}
+
+			assets, err := ResolveMatchAssets(summary, store, "/api/static")
+			if err != nil {
+				t.Fatalf("ResolveMatchAssets: %v", err)
+			}
+			if len(assets) != 1 {
+				t.Fatalf("assets = %+v, want 1 match", assets)
+			}
+			got := assets[0]
+			if got.DataVersion != tt.wantData {
+				t.Errorf("DataVersion = %s, want %s", got.DataVersion, tt.wantData)
+			}
+
+			names := func(ids []ResolvedID) []string {
+				var list []string
+				for _, id := range ids {
+					list = append(list, id.Name)
+				}
+				return list
+			}
+
+			for _, c := range []struct {
+				label string
+				got   []ResolvedID
+				want  []string
+			}{
+				// バンなし（-1）は除き、不明なIDは数値のまま
+				{"Bans", got.Bans, []string{"アーリ" + tt.suffix, "999"}},
+				{"Items", got.Items, []string{"ワード トーテム" + tt.suffix, "ワード トーテム" + tt.suffix}},
+				{"Runes", got.Runes, []string{"覇道" + tt.suffix, "電撃" + tt.suffix}},
+				{"SummonerSpells", got.SummonerSpells, []string{"フラッシュ" + tt.suffix, "14"}},
+			} {
+				if names := names(c.got); !slices.Equal(names, c.want) {
+					t.Errorf("%s = %v, want %v", c.label, names, c.want)
+				}
+			}
+
+			// サーバーのディレクトリではなく配信するURLからのパス
+			wantIcons := map[string]string{
+				"ban":   "/api/static/" + tt.wantData + "/img/champion/Ahri.png",
+				"item":  "/api/static/" + tt.wantData + "/img/item/3340.png",
+				"rune":  "/api/static/img/perk-images/Styles/Domination/Electrocute/Electrocute.png",
+				"spell": "/api/static/" + tt.wantData + "/img/spell/SummonerFlash.png",
+			}
+			gotIcons := map[string]string{
+				"ban":   got.Bans[0].Icon,
+				"item":  got.Items[0].Icon,
+				"rune":  got.Runes[1].Icon,
+				"spell": got.SummonerSpells[0].Icon,
+			}
+			for key, want := range wantIcons {
+				if gotIcons[key] != want {
+					t.Errorf("%s icon = %s, want %s", key, gotIcons[key], want)
+				}
+			}
+			if got.Bans[1].Icon != "" {
+				t.Errorf("不明なチャンピオンのアイコン = %s, want empty", got.Bans[1].Icon)
+			}
+		})
+	}
+}
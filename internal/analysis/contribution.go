@@ -0,0 +1,168 @@
+package analysis
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+// 1試合分のチーム内での貢献度（割合は %、チームの合計が0の場合は0）
+type MatchContribution struct {
+	KillParticipation float64 `json:"killParticipation"` // (キル+アシスト) / チームのキル
+	DamageShare       float64 `json:"damageShare"`       // チャンピオンへのダメージのチーム内割合
+	GoldShare         float64 `json:"goldShare"`         // 獲得ゴールドのチーム内割合
+	DamageTakenShare  float64 `json:"damageTakenShare"`  // 被ダメージのチーム内割合
+	VisionPerMinute   float64 `json:"visionPerMinute"`
+}
+
+// 貢献度の1試合あたりの平均
+type ContributionStats struct {
+	Games                    int     `json:"games"`
+	AverageKillParticipation float64 `json:"averageKillParticipation"`
+	AverageDamageShare       float64 `json:"averageDamageShare"`
+	AverageGoldShare         float64 `json:"averageGoldShare"`
+	AverageDamageTakenShare  float64 `json:"averageDamageTakenShare"`
+	AverageVisionPerMinute   float64 `json:"averageVisionPerMinute"` // 視界スコアの合計 / 試合時間の合計
+}
+
+type ChampionContributionStats struct {
+	ChampionName string `json:"championName"`
+	ContributionStats
+}
+
+// チーム内での貢献度のレポート
+type ContributionReport struct {
+	Overall    ContributionStats            `json:"overall"`
+	ByChampion []ChampionContributionStats  `json:"byChampion"` // 試合数の多い順
+	ByPosition map[string]ContributionStats `json:"byPosition"`
+}
+
+// 分析対象の試合からチーム内での貢献度のレポートを作成
+func CalculateContribution(summary *riot.PlayerMatchSummary) *ContributionReport {
+	report := &ContributionReport{
+		ByChampion: []ChampionContributionStats{},
+		ByPosition: make(map[string]ContributionStats),
+	}
+
+	var overall contributionTotals
+	champions := make(map[string]*contributionTotals)
+	positions := make(map[string]*contributionTotals)
+
+	for i := range summary.MatchHistory {
+		match := &summary.MatchHistory[i]
+
+		player := riot.FindParticipant(match, summary.Account.PUUID)
+		if player == nil {
+			continue
+		}
+
+		contribution := MatchContributionFor(match, player)
+
+		overall.add(match, player, contribution)
+		if champions[player.ChampionName] == nil {
+			champions[player.ChampionName] = &contributionTotals{}
+		}
+		champions[player.ChampionName].add(match, player, contribution)
+		if isTeamPosition(player.TeamPosition) {
+			if positions[player.TeamPosition] == nil {
+				positions[player.TeamPosition] = &contributionTotals{}
+			}
+			positions[player.TeamPosition].add(match, player, contribution)
+		}
+	}
+
+	report.Overall = overall.stats()
+	for name, totals := range champions {
+		report.ByChampion = append(report.ByChampion, ChampionContributionStats{
+			ChampionName:      name,
+			ContributionStats: totals.stats(),
+		})
+	}
+	for position, totals := range positions {
+		report.ByPosition[position] = totals.stats()
+	}
+
+	slices.SortFunc(report.ByChampion, func(a, b ChampionContributionStats) int {
+		if c := cmp.Compare(b.Games, a.Games); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ChampionName, b.ChampionName)
+	})
+
+	return report
+}
+
+// 同じチームの参加者の合計と比較して1試合分の貢献度を計算
+func MatchContributionFor(match *riot.MatchDetail, player *riot.Participant) MatchContribution {
+	var teamKills, teamDamage, teamGold, teamDamageTaken int
+	for _, p := range match.Info.Participants {
+		if p.TeamID != player.TeamID {
+			continue
+		}
+		teamKills += p.Kills
+		teamDamage += p.TotalDamageDealtToChampions
+		teamGold += p.GoldEarned
+		teamDamageTaken += p.TotalDamageTaken
+	}
+
+	contribution := MatchContribution{
+		KillParticipation: percentage(player.Kills+player.Assists, teamKills),
+		DamageShare:       percentage(player.TotalDamageDealtToChampions, teamDamage),
+		GoldShare:         percentage(player.GoldEarned, teamGold),
+		DamageTakenShare:  percentage(player.TotalDamageTaken, teamDamageTaken),
+	}
+	if match.Info.GameDuration > 0 {
+		contribution.VisionPerMinute = float64(player.VisionScore) / (float64(match.Info.GameDuration) / 60)
+	}
+
+	return contribution
+}
+
+// 割合（%、全体が0の場合は0）
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+type contributionTotals struct {
+	games                                     int
+	killParticipation, damageShare, goldShare float64
+	damageTakenShare                          float64
+	visionScore                               int
+	seconds                                   int // 試合時間の合計
+}
+
+func (t *contributionTotals) add(match *riot.MatchDetail, player *riot.Participant, c MatchContribution) {
+	t.games++
+	t.killParticipation += c.KillParticipation
+	t.damageShare += c.DamageShare
+	t.goldShare += c.GoldShare
+	t.damageTakenShare += c.DamageTakenShare
+	t.visionScore += player.VisionScore
+	t.seconds += match.Info.GameDuration
+}
+
+func (t *contributionTotals) stats() ContributionStats {
+	if t.games == 0 {
+		return ContributionStats{}
+	}
+
+	// 視界スコアはポジション別の成績と同じく試合時間の合計で割る
+	var visionPerMinute float64
+	if t.seconds > 0 {
+		visionPerMinute = float64(t.visionScore) / (float64(t.seconds) / 60)
+	}
+
+	n := float64(t.games)
+	return ContributionStats{
+		Games:                    t.games,
+		AverageKillParticipation: t.killParticipation / n,
+		AverageDamageShare:       t.damageShare / n,
+		AverageGoldShare:         t.goldShare / n,
+		AverageDamageTakenShare:  t.damageTakenShare / n,
+		AverageVisionPerMinute:   visionPerMinute,
+	}
+}
@@ -0,0 +1,44 @@
+package analysis
+
+import "testing"
+
+func TestContributionVisionPerMinute(t *testing.T) {
+	tests := []struct {
+		name    string
+		matches []string
+	}{
+		{name: "1試合", matches: []string{"00"}},
+		{name: "試合時間が異なる", matches: []string{"03", "07"}},
+		{name: "全試合", matches: []string{"00", "01", "02", "03", "04", "05", "06", "07", "08", "09", "10", "11"}},
+	}
+
+	fixtures := loadFixtureMatches(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := fixtureSummary(fixtures, tt.matches)
+
+			// 視界スコアの合計 / 試合時間の合計
+			var vision, seconds int
+			for i := range summary.MatchHistory {
+				match := &summary.MatchHistory[i]
+				vision += fixturePlayer(t, match).VisionScore
+				seconds += match.Info.GameDuration
+			}
+			want := float64(vision) / (float64(seconds) / 60)
+
+			stats := CalculatePlayerStats(summary)
+			if got := stats.Contribution.Overall.AverageVisionPerMinute; !approxEqual(got, want) {
+				t.Errorf("Overall.AverageVisionPerMinute = %v, want %v", got, want)
+			}
+
+			// ポジション別の成績と同じ値になる
+			for position, performance := range stats.PositionPerformance {
+				got := stats.Contribution.ByPosition[position].AverageVisionPerMinute
+				if !approxEqual(got, performance.VisionPerMinute) {
+					t.Errorf("%s: 貢献度 %v, ポジション別 %v", position, got, performance.VisionPerMinute)
+				}
+			}
+		})
+	}
+}
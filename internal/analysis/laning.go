@@ -0,0 +1,291 @@
+package analysis
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+// レーン戦の比較に使う時間（分）
+const (
+	laneEarlyMinute = 10
+	laneLateMinute  = 15
+)
+
+// 1試合分のレーン戦の結果（差分は対面との比較で、プラスが有利）
+type MatchLaning struct {
+	MatchID          string   `json:"matchId"`
+	ChampionName     string   `json:"championName"`
+	Position         string   `json:"position"`
+	OpponentChampion string   `json:"opponentChampion"`
+	Win              bool     `json:"win"`
+	CSAt10           int      `json:"csAt10"`
+	CSDiffAt10       int      `json:"csDiffAt10"`
+	GoldDiffAt10     int      `json:"goldDiffAt10"`
+	XPDiffAt10       int      `json:"xpDiffAt10"`
+	GoldDiffAt15     *int     `json:"goldDiffAt15,omitempty"`     // 15分前に終了した試合は nil
+	XPDiffAt15       *int     `json:"xpDiffAt15,omitempty"`       // 15分前に終了した試合は nil
+	FirstDeathMinute *float64 `json:"firstDeathMinute,omitempty"` // 一度も死亡していない場合は nil
+}
+
+// レーン戦の平均値
+type LaningStats struct {
+	Games                   int     `json:"games"`
+	AverageCSAt10           float64 `json:"averageCSAt10"`
+	AverageCSDiffAt10       float64 `json:"averageCSDiffAt10"`
+	AverageGoldDiffAt10     float64 `json:"averageGoldDiffAt10"`
+	AverageXPDiffAt10       float64 `json:"averageXPDiffAt10"`
+	AverageGoldDiffAt15     float64 `json:"averageGoldDiffAt15"`
+	AverageXPDiffAt15       float64 `json:"averageXPDiffAt15"`
+	LaneLeadRate            float64 `json:"laneLeadRate"`            // 15分時点でゴールド有利だった割合（%）
+	AverageFirstDeathMinute float64 `json:"averageFirstDeathMinute"` // 死亡した試合のみの平均
+	DeathlessGames          int     `json:"deathlessGames"`
+}
+
+type ChampionLaningStats struct {
+	ChampionName string `json:"championName"`
+	LaningStats
+}
+
+// タイムラインから集計したレーン戦のレポート
+type LaningReport struct {
+	Overall        LaningStats            `json:"overall"`
+	ByChampion     []ChampionLaningStats  `json:"byChampion"` // 試合数の多い順
+	ByPosition     map[string]LaningStats `json:"byPosition"`
+	Matches        []MatchLaning          `json:"matches"`
+	SkippedMatches int                    `json:"skippedMatches"` // タイムラインや対面がなく集計できなかった試合数
+}
+
+// 分析結果のタイムラインからレーン戦のレポートを作成（タイムライン未取得の場合は nil）
+func CalculateLaning(summary *riot.PlayerMatchSummary) *LaningReport {
+	if len(summary.Timelines) == 0 {
+		return nil
+	}
+
+	report := &LaningReport{
+		ByChampion: []ChampionLaningStats{},
+		ByPosition: make(map[string]LaningStats),
+		Matches:    []MatchLaning{},
+	}
+
+	var overall laningTotals
+	champions := make(map[string]*laningTotals)
+	positions := make(map[string]*laningTotals)
+
+	for i := range summary.MatchHistory {
+		match := &summary.MatchHistory[i]
+
+		timeline, ok := summary.Timelines[match.Metadata.MatchID]
+		if !ok {
+			report.SkippedMatches++
+			continue
+		}
+
+		laning, ok := MatchLaningFor(match, timeline, summary.Account.PUUID)
+		if !ok {
+			report.SkippedMatches++
+			continue
+		}
+
+		report.Matches = append(report.Matches, laning)
+
+		overall.add(laning)
+		if champions[laning.ChampionName] == nil {
+			champions[laning.ChampionName] = &laningTotals{}
+		}
+		champions[laning.ChampionName].add(laning)
+		if positions[laning.Position] == nil {
+			positions[laning.Position] = &laningTotals{}
+		}
+		positions[laning.Position].add(laning)
+	}
+
+	report.Overall = overall.stats()
+	for name, totals := range champions {
+		report.ByChampion = append(report.ByChampion, ChampionLaningStats{
+			ChampionName: name,
+			LaningStats:  totals.stats(),
+		})
+	}
+	for position, totals := range positions {
+		report.ByPosition[position] = totals.stats()
+	}
+
+	slices.SortFunc(report.ByChampion, func(a, b ChampionLaningStats) int {
+		if c := cmp.Compare(b.Games, a.Games); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ChampionName, b.ChampionName)
+	})
+
+	return report
+}
+
+// 1試合分のレーン戦の結果を計算
+// 対面（同じ TeamPosition の敵）がいない試合や10分前に終了した試合は ok=false
+func MatchLaningFor(match *riot.MatchDetail, timeline *riot.MatchTimeline, puuid string) (MatchLaning, bool) {
+	player, opponent := laneOpponents(match, puuid)
+	if player == nil || opponent == nil {
+		return MatchLaning{}, false
+	}
+
+	playerID := timelineParticipantID(timeline, player)
+	opponentID := timelineParticipantID(timeline, opponent)
+
+	early := frameAt(timeline, laneEarlyMinute)
+	if early == nil {
+		return MatchLaning{}, false
+	}
+
+	playerEarly, ok1 := early.Participant(playerID)
+	opponentEarly, ok2 := early.Participant(opponentID)
+	if !ok1 || !ok2 {
+		return MatchLaning{}, false
+	}
+
+	laning := MatchLaning{
+		MatchID:          match.Metadata.MatchID,
+		ChampionName:     player.ChampionName,
+		Position:         player.TeamPosition,
+		OpponentChampion: opponent.ChampionName,
+		Win:              player.Win,
+		CSAt10:           playerEarly.CS(),
+		CSDiffAt10:       playerEarly.CS() - opponentEarly.CS(),
+		GoldDiffAt10:     playerEarly.TotalGold - opponentEarly.TotalGold,
+		XPDiffAt10:       playerEarly.XP - opponentEarly.XP,
+	}
+
+	if late := frameAt(timeline, laneLateMinute); late != nil {
+		playerLate, ok1 := late.Participant(playerID)
+		opponentLate, ok2 := late.Participant(opponentID)
+		if ok1 && ok2 {
+			goldDiff := playerLate.TotalGold - opponentLate.TotalGold
+			xpDiff := playerLate.XP - opponentLate.XP
+			laning.GoldDiffAt15 = &goldDiff
+			laning.XPDiffAt15 = &xpDiff
+		}
+	}
+
+	if timestamp, ok := firstDeath(timeline, playerID); ok {
+		minute := float64(timestamp) / 60000
+		laning.FirstDeathMinute = &minute
+	}
+
+	return laning, true
+}
+
+// プレイヤーと対面の参加者データ
+func laneOpponents(match *riot.MatchDetail, puuid string) (player, opponent *riot.Participant) {
+	player = riot.FindParticipant(match, puuid)
+	if player == nil || !isTeamPosition(player.TeamPosition) {
+		return player, nil
+	}
+
+	for i := range match.Info.Participants {
+		p := &match.Info.Participants[i]
+		if p.TeamID != player.TeamID && p.TeamPosition == player.TeamPosition {
+			return player, p
+		}
+	}
+	return player, nil
+}
+
+// タイムライン上の参加者ID（PUUIDで対応付け、見つからなければマッチ詳細の値）
+func timelineParticipantID(timeline *riot.MatchTimeline, participant *riot.Participant) int {
+	for _, p := range timeline.Info.Participants {
+		if p.PUUID == participant.PUUID {
+			return p.ParticipantID
+		}
+	}
+	return participant.ParticipantID
+}
+
+// 指定した分以降で最初のフレーム（試合がその時間まで続いていない場合は nil）
+func frameAt(timeline *riot.MatchTimeline, minute int) *riot.TimelineFrame {
+	target := int64(minute) * 60000
+	for i := range timeline.Info.Frames {
+		if timeline.Info.Frames[i].Timestamp >= target {
+			return &timeline.Info.Frames[i]
+		}
+	}
+	return nil
+}
+
+// 最初に死亡した時刻（ミリ秒）
+func firstDeath(timeline *riot.MatchTimeline, participantID int) (int64, bool) {
+	for _, frame := range timeline.Info.Frames {
+		for _, event := range frame.Events {
+			if event.Type == riot.EventChampionKill && event.VictimID == participantID {
+				return event.Timestamp, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// 平均値計算用の合計
+type laningTotals struct {
+	games             int
+	cs10              int
+	csDiff10          int
+	goldDiff10        int
+	xpDiff10          int
+	games15           int
+	goldDiff15        int
+	xpDiff15          int
+	leads15           int
+	deathGames        int
+	firstDeathMinutes float64
+}
+
+func (t *laningTotals) add(laning MatchLaning) {
+	t.games++
+	t.cs10 += laning.CSAt10
+	t.csDiff10 += laning.CSDiffAt10
+	t.goldDiff10 += laning.GoldDiffAt10
+	t.xpDiff10 += laning.XPDiffAt10
+
+	if laning.GoldDiffAt15 != nil && laning.XPDiffAt15 != nil {
+		t.games15++
+		t.goldDiff15 += *laning.GoldDiffAt15
+		t.xpDiff15 += *laning.XPDiffAt15
+		if *laning.GoldDiffAt15 > 0 {
+			t.leads15++
+		}
+	}
+
+	if laning.FirstDeathMinute != nil {
+		t.deathGames++
+		t.firstDeathMinutes += *laning.FirstDeathMinute
+	}
+}
+
+func (t *laningTotals) stats() LaningStats {
+	stats := LaningStats{
+		Games:          t.games,
+		DeathlessGames: t.games - t.deathGames,
+	}
+	if t.games == 0 {
+		return stats
+	}
+
+	games := float64(t.games)
+	stats.AverageCSAt10 = float64(t.cs10) / games
+	stats.AverageCSDiffAt10 = float64(t.csDiff10) / games
+	stats.AverageGoldDiffAt10 = float64(t.goldDiff10) / games
+	stats.AverageXPDiffAt10 = float64(t.xpDiff10) / games
+
+	if t.games15 > 0 {
+		games15 := float64(t.games15)
+		stats.AverageGoldDiffAt15 = float64(t.goldDiff15) / games15
+		stats.AverageXPDiffAt15 = float64(t.xpDiff15) / games15
+		stats.LaneLeadRate = float64(t.leads15) / games15 * 100
+	}
+
+	if t.deathGames > 0 {
+		stats.AverageFirstDeathMinute = t.firstDeathMinutes / float64(t.deathGames)
+	}
+
+	return stats
+}
@@ -0,0 +1,169 @@
+package analysis
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+// 進行中の試合の参加者一覧と各参加者の直近成績
+type LiveGameReport struct {
+	GameID          int64                 `json:"gameId"`
+	GameMode        string                `json:"gameMode"`
+	QueueID         int                   `json:"queueId"`
+	MapID           int                   `json:"mapId"`
+	StartTime       time.Time             `json:"startTime"`  // ロード中はゼロ値
+	GameLength      int64                 `json:"gameLength"` // 秒
+	BannedChampions []riot.BannedChampion `json:"bannedChampions"`
+	Participants    []LiveParticipant     `json:"participants"` // チームID順
+}
+
+type LiveParticipant struct {
+	PUUID        string            `json:"puuid"`
+	RiotID       string            `json:"riotId"`
+	TeamID       int               `json:"teamId"`
+	ChampionID   int               `json:"championId"`
+	ChampionName string            `json:"championName,omitempty"` // 試合履歴から解決できない場合は空
+	Bot          bool              `json:"bot"`
+	Spell1ID     int               `json:"spell1Id"`
+	Spell2ID     int               `json:"spell2Id"`
+	Perks        riot.Perks        `json:"perks"`
+	Rank         *riot.LeagueEntry `json:"rank"` // ソロランク（未ランクは null）
+	Form         *ParticipantForm  `json:"form,omitempty"`
+	Error        string            `json:"error,omitempty"` // 直近成績を取得できなかった理由
+}
+
+// 直近の試合から見た調子
+type ParticipantForm struct {
+	RecentGames            int     `json:"recentGames"`
+	RecentWins             int     `json:"recentWins"`
+	RecentWinRate          float64 `json:"recentWinRate"`
+	RecentKDA              float64 `json:"recentKDA"`
+	MainChampion           string  `json:"mainChampion"`
+	MainChampionGames      int     `json:"mainChampionGames"`
+	CurrentChampionGames   int     `json:"currentChampionGames"`
+	CurrentChampionWinRate float64 `json:"currentChampionWinRate"`
+}
+
+// 参加者ごとに取得した直近の試合・ランクから試合中の参加者一覧を作成
+// histories・leagues は PUUID をキーとし、取得に失敗した参加者は errs に理由を入れる
+func BuildLiveGameReport(game *riot.ActiveGame, histories map[string][]riot.MatchDetail, leagues map[string][]riot.LeagueEntry, errs map[string]error) *LiveGameReport {
+	report := &LiveGameReport{
+		GameID:          game.GameID,
+		GameMode:        game.GameMode,
+		QueueID:         game.GameQueueConfigID,
+		MapID:           game.MapID,
+		StartTime:       game.StartTime(),
+		GameLength:      game.GameLength,
+		BannedChampions: game.BannedChampions,
+		Participants:    []LiveParticipant{},
+	}
+	if report.BannedChampions == nil {
+		report.BannedChampions = []riot.BannedChampion{}
+	}
+
+	// チャンピオン名は全参加者の試合履歴から解決する
+	names := make(map[int]string)
+	for _, matches := range histories {
+		for i := range matches {
+			for _, p := range matches[i].Info.Participants {
+				names[p.ChampionID] = p.ChampionName
+			}
+		}
+	}
+
+	for _, p := range game.Participants {
+		participant := LiveParticipant{
+			PUUID:        p.PUUID,
+			RiotID:       p.RiotID,
+			TeamID:       p.TeamID,
+			ChampionID:   p.ChampionID,
+			ChampionName: names[p.ChampionID],
+			Bot:          p.Bot,
+			Spell1ID:     p.Spell1ID,
+			Spell2ID:     p.Spell2ID,
+			Perks:        p.Perks,
+		}
+
+		if !p.Bot && p.PUUID != "" {
+			participant.Rank = riot.FindLeagueEntry(leagues[p.PUUID], riot.QueueTypeSolo)
+			if err := errs[p.PUUID]; err != nil {
+				participant.Error = err.Error()
+			} else {
+				participant.Form = CalculateParticipantForm(p.PUUID, p.ChampionID, histories[p.PUUID])
+			}
+		}
+
+		report.Participants = append(report.Participants, participant)
+	}
+
+	slices.SortStableFunc(report.Participants, func(a, b LiveParticipant) int {
+		return cmp.Compare(a.TeamID, b.TeamID)
+	})
+
+	return report
+}
+
+// 直近の試合から勝率・メインチャンピオン・使用中チャンピオンの経験を集計
+func CalculateParticipantForm(puuid string, championID int, matches []riot.MatchDetail) *ParticipantForm {
+	form := &ParticipantForm{}
+
+	type championTotals struct {
+		name        string
+		games, wins int
+	}
+
+	var kills, deaths, assists int
+	champions := make(map[int]*championTotals)
+
+	for i := range matches {
+		player := riot.FindParticipant(&matches[i], puuid)
+		if player == nil {
+			continue
+		}
+
+		form.RecentGames++
+		if player.Win {
+			form.RecentWins++
+		}
+		kills += player.Kills
+		deaths += player.Deaths
+		assists += player.Assists
+
+		totals := champions[player.ChampionID]
+		if totals == nil {
+			totals = &championTotals{name: player.ChampionName}
+			champions[player.ChampionID] = totals
+		}
+		totals.games++
+		if player.Win {
+			totals.wins++
+		}
+	}
+
+	if form.RecentGames == 0 {
+		return form
+	}
+
+	form.RecentWinRate = float64(form.RecentWins) / float64(form.RecentGames) * 100
+	form.RecentKDA = kdaRatio(kills, deaths, assists, form.RecentGames)
+
+	// 試合数が同じ場合はチャンピオンIDの小さい方（結果を安定させるため）
+	mainID := -1
+	for id, totals := range champions {
+		if main := champions[mainID]; main == nil || totals.games > main.games || (totals.games == main.games && id < mainID) {
+			mainID = id
+		}
+	}
+	form.MainChampion = champions[mainID].name
+	form.MainChampionGames = champions[mainID].games
+
+	if current := champions[championID]; current != nil {
+		form.CurrentChampionGames = current.games
+		form.CurrentChampionWinRate = float64(current.wins) / float64(current.games) * 100
+	}
+
+	return form
+}
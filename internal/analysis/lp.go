@@ -0,0 +1,270 @@
+package analysis
+
+import (
+	"slices"
+	"time"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+// ティアの序列（マスター以上はディビジョンがなく LP が連続する）
+var tierOrder = []string{
+	"IRON", "BRONZE", "SILVER", "GOLD", "PLATINUM", "EMERALD", "DIAMOND",
+	"MASTER", "GRANDMASTER", "CHALLENGER",
+}
+
+var divisionOrder = map[string]int{"IV": 0, "III": 1, "II": 2, "I": 3}
+
+// league-v4 のキュー種別に対応するキューID
+var leagueQueueIDs = map[string]int{
+	riot.QueueTypeSolo: riot.QueueSoloRanked,
+	riot.QueueTypeFlex: riot.QueueFlexRanked,
+}
+
+// アイアンIV 0LP を0とした通算LP（1ディビジョン100LP、1ティア400LP）
+func TotalLP(entry *riot.LeagueEntry) int {
+	tier := slices.Index(tierOrder, entry.Tier)
+	if tier < 0 {
+		return 0
+	}
+
+	master := slices.Index(tierOrder, "MASTER")
+	if tier >= master {
+		return master*400 + entry.LeaguePoints
+	}
+	return tier*400 + divisionOrder[entry.Rank]*100 + entry.LeaguePoints
+}
+
+// LP 推移の1点
+type LPPoint struct {
+	Time         time.Time `json:"time"`
+	Tier         string    `json:"tier"`
+	Rank         string    `json:"rank"`
+	LeaguePoints int       `json:"leaguePoints"`
+	TotalLP      int       `json:"totalLP"`
+	Wins         int       `json:"wins"`
+	Losses       int       `json:"losses"`
+}
+
+// 1試合あたりの推定LP増減
+type LPGameEstimate struct {
+	MatchID           string    `json:"matchId"`
+	GameEnd           time.Time `json:"gameEnd"`
+	ChampionName      string    `json:"championName"`
+	Win               bool      `json:"win"`
+	EstimatedLPChange float64   `json:"estimatedLPChange"`
+}
+
+// キューごとの LP 推移
+type QueueLPHistory struct {
+	QueueType     string           `json:"queueType"`
+	Series        []LPPoint        `json:"series"` // 古い順
+	Games         []LPGameEstimate `json:"games"`  // 新しい順
+	NetLPChange   int              `json:"netLPChange"`
+	AverageLPGain float64          `json:"averageLPGain"` // 勝利1回あたりの推定獲得LP
+	AverageLPLoss float64          `json:"averageLPLoss"` // 敗北1回あたりの推定損失LP（正の値）
+}
+
+type LPHistoryReport struct {
+	Queues []QueueLPHistory `json:"queues"`
+}
+
+// スナップショット間の LP 変化をその間の試合に割り当てる（スナップショットがない場合は nil）
+func CalculateLPHistory(summary *riot.PlayerMatchSummary) *LPHistoryReport {
+	if len(summary.LeagueHistory) == 0 {
+		return nil
+	}
+
+	report := &LPHistoryReport{Queues: []QueueLPHistory{}}
+
+	for _, queueType := range []string{riot.QueueTypeSolo, riot.QueueTypeFlex} {
+		series := lpSeries(summary.LeagueHistory, queueType)
+		if len(series) == 0 {
+			continue
+		}
+
+		history := QueueLPHistory{
+			QueueType:   queueType,
+			Series:      series,
+			Games:       []LPGameEstimate{},
+			NetLPChange: series[len(series)-1].TotalLP - series[0].TotalLP,
+		}
+		estimateLPPerGame(&history, summary, leagueQueueIDs[queueType])
+
+		report.Queues = append(report.Queues, history)
+	}
+
+	return report
+}
+
+func lpSeries(snapshots []riot.LeagueSnapshot, queueType string) []LPPoint {
+	var series []LPPoint
+	for _, snapshot := range snapshots {
+		entry := riot.FindLeagueEntry(snapshot.Entries, queueType)
+		if entry == nil {
+			continue
+		}
+
+		series = append(series, LPPoint{
+			Time:         snapshot.TakenAt,
+			Tier:         entry.Tier,
+			Rank:         entry.Rank,
+			LeaguePoints: entry.LeaguePoints,
+			TotalLP:      TotalLP(entry),
+			Wins:         entry.Wins,
+			Losses:       entry.Losses,
+		})
+	}
+	return series
+}
+
+// 連続する2つのスナップショットの間
+type lpWindow struct {
+	from, to     LPPoint
+	wins, losses int
+	change       int
+}
+
+// 各区間の LP 変化を勝敗数で按分し、区間内の試合に割り当てる
+// 勝ちのみ・負けのみの区間は正確に求まり、混在する区間はその平均値の比で按分する
+func estimateLPPerGame(history *QueueLPHistory, summary *riot.PlayerMatchSummary, queueID int) {
+	var windows []lpWindow
+	for i := 1; i < len(history.Series); i++ {
+		from, to := history.Series[i-1], history.Series[i]
+		w := lpWindow{
+			from:   from,
+			to:     to,
+			wins:   to.Wins - from.Wins,
+			losses: to.Losses - from.Losses,
+			change: to.TotalLP - from.TotalLP,
+		}
+		// シーズンリセット等で勝敗数が減った区間は除外
+		if w.wins < 0 || w.losses < 0 || w.wins+w.losses == 0 {
+			continue
+		}
+		windows = append(windows, w)
+	}
+
+	// 勝ちのみ・負けのみの区間から1試合あたりの平均を求める
+	var gainLP, gainGames, lossLP, lossGames int
+	for _, w := range windows {
+		switch {
+		case w.losses == 0:
+			gainLP += w.change
+			gainGames += w.wins
+		case w.wins == 0:
+			lossLP -= w.change
+			lossGames += w.losses
+		}
+	}
+	var baseGain, baseLoss float64
+	if gainGames > 0 {
+		baseGain = float64(gainLP) / float64(gainGames)
+	}
+	if lossGames > 0 {
+		baseLoss = float64(lossLP) / float64(lossGames)
+	}
+
+	var totalGain, totalLoss float64
+	var estimatedWins, estimatedLosses int
+
+	for _, w := range windows {
+		gain, loss, ok := estimateWindow(w, baseGain, baseLoss)
+		if !ok {
+			continue
+		}
+
+		totalGain += gain * float64(w.wins)
+		totalLoss += loss * float64(w.losses)
+		estimatedWins += w.wins
+		estimatedLosses += w.losses
+
+		for _, match := range summary.MatchHistory {
+			if match.Info.QueueID != queueID {
+				continue
+			}
+			end := gameEnd(&match)
+			if !end.After(w.from.Time) || end.After(w.to.Time) {
+				continue
+			}
+
+			player := riot.FindParticipant(&match, summary.Account.PUUID)
+			if player == nil {
+				continue
+			}
+
+			estimate := LPGameEstimate{
+				MatchID:           match.Metadata.MatchID,
+				GameEnd:           end,
+				ChampionName:      player.ChampionName,
+				Win:               player.Win,
+				EstimatedLPChange: -loss,
+			}
+			if player.Win {
+				estimate.EstimatedLPChange = gain
+			}
+			history.Games = append(history.Games, estimate)
+		}
+	}
+
+	if estimatedWins > 0 {
+		history.AverageLPGain = totalGain / float64(estimatedWins)
+	}
+	if estimatedLosses > 0 {
+		history.AverageLPLoss = totalLoss / float64(estimatedLosses)
+	}
+
+	slices.SortFunc(history.Games, func(a, b LPGameEstimate) int {
+		return b.GameEnd.Compare(a.GameEnd)
+	})
+}
+
+// 区間内の勝利・敗北1回あたりの LP（loss は正の値）
+func estimateWindow(w lpWindow, baseGain, baseLoss float64) (gain, loss float64, ok bool) {
+	change := float64(w.change)
+
+	switch {
+	case w.losses == 0:
+		return change / float64(w.wins), 0, true
+	case w.wins == 0:
+		return 0, -change / float64(w.losses), true
+	}
+
+	wins, losses := float64(w.wins), float64(w.losses)
+
+	switch {
+	case baseGain > 0 && baseLoss > 0:
+		// 平均の獲得・損失LPの比を保ったまま区間の変化量に合わせる
+		if expected := wins*baseGain - losses*baseLoss; expected != 0 {
+			if scale := change / expected; scale > 0 {
+				return baseGain * scale, baseLoss * scale, true
+			}
+		}
+	case baseGain > 0:
+		// 獲得LPのみ分かっている場合は残りを敗北に割り当てる
+		if loss := (wins*baseGain - change) / losses; loss > 0 {
+			return baseGain, loss, true
+		}
+	case baseLoss > 0:
+		if gain := (change + losses*baseLoss) / wins; gain > 0 {
+			return gain, baseLoss, true
+		}
+	}
+
+	// 比が不明な場合は獲得と損失が同程度と仮定
+	if w.wins != w.losses {
+		if perGame := change / (wins - losses); perGame > 0 {
+			return perGame, perGame, true
+		}
+	}
+
+	return 0, 0, false
+}
+
+// 試合の終了時刻（gameEndTimestamp がない古いデータは開始時刻と試合時間から計算）
+func gameEnd(match *riot.MatchDetail) time.Time {
+	if match.Info.GameEndTime > 0 {
+		return time.UnixMilli(match.Info.GameEndTime)
+	}
+	return time.UnixMilli(match.Info.GameStartTime).Add(time.Duration(match.Info.GameDuration) * time.Second)
+}
@@ -0,0 +1,227 @@
+package analysis
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+var lpEpoch = time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+
+// lpEpoch から hour 時間後のソロランクのスナップショット
+func lpSnapshot(hour int, tier, rank string, lp, wins, losses int) riot.LeagueSnapshot {
+	return riot.LeagueSnapshot{
+		PUUID:   fixturePUUID,
+		TakenAt: lpEpoch.Add(time.Duration(hour) * time.Hour),
+		Entries: []riot.LeagueEntry{{
+			QueueType: riot.QueueTypeSolo, Tier: tier, Rank: rank, LeaguePoints: lp, Wins: wins, Losses: losses,
+		}},
+	}
+}
+
+// lpEpoch から minute 分後に終了した試合
+func lpMatch(n, minute int, queueID int, win bool) riot.MatchDetail {
+	var match riot.MatchDetail
+	match.Metadata.MatchID = fmt.Sprintf("JP1_%d", n)
+	match.Info.QueueID = queueID
+	match.Info.GameEndTime = lpEpoch.Add(time.Duration(minute) * time.Minute).UnixMilli()
+	match.Info.Participants = []riot.Participant{{PUUID: fixturePUUID, ChampionName: "Ahri", Win: win}}
+	return match
+}
+
+func TestTotalLP(t *testing.T) {
+	tests := []struct {
+		tier, rank string
+		lp         int
+		want       int
+	}{
+		{"IRON", "IV", 0, 0},
+		{"IRON", "I", 99, 399},
+		{"GOLD", "II", 45, 3*400 + 2*100 + 45},
+		{"DIAMOND", "I", 100, 6*400 + 3*100 + 100},
+		{"MASTER", "", 120, 7*400 + 120},
+		{"CHALLENGER", "I", 1500, 7*400 + 1500},
+		{"UNRANKED", "", 50, 0},
+	}
+
+	for _, tt := range tests {
+		entry := &riot.LeagueEntry{Tier: tt.tier, Rank: tt.rank, LeaguePoints: tt.lp}
+		if got := TotalLP(entry); got != tt.want {
+			t.Errorf("TotalLP(%s %s %dLP) = %d, want %d", tt.tier, tt.rank, tt.lp, got, tt.want)
+		}
+	}
+}
+
+func TestCalculateLPHistory(t *testing.T) {
+	type game struct {
+		matchID string
+		change  float64
+	}
+
+	tests := []struct {
+		name      string
+		snapshots []riot.LeagueSnapshot
+		matches   []riot.MatchDetail
+
+		wantNet   int
+		wantGames []game // 新しい順
+		wantGain  float64
+		wantLoss  float64
+	}{
+		{
+			name:      "スナップショット1つ",
+			snapshots: []riot.LeagueSnapshot{lpSnapshot(0, "GOLD", "II", 45, 10, 10)},
+			matches:   []riot.MatchDetail{lpMatch(1, 30, riot.QueueSoloRanked, true)},
+			wantGames: []game{},
+		},
+		{
+			name: "昇格をまたぐ勝利",
+			snapshots: []riot.LeagueSnapshot{
+				lpSnapshot(0, "GOLD", "I", 80, 10, 10),
+				lpSnapshot(1, "PLATINUM", "IV", 5, 11, 10),
+			},
+			matches:   []riot.MatchDetail{lpMatch(1, 30, riot.QueueSoloRanked, true)},
+			wantNet:   25,
+			wantGames: []game{{"JP1_1", 25}},
+			wantGain:  25,
+		},
+		{
+			name: "降格をまたぐ敗北",
+			snapshots: []riot.LeagueSnapshot{
+				lpSnapshot(0, "PLATINUM", "IV", 10, 10, 10),
+				lpSnapshot(1, "GOLD", "I", 85, 10, 11),
+			},
+			matches:   []riot.MatchDetail{lpMatch(1, 30, riot.QueueSoloRanked, false)},
+			wantNet:   -25,
+			wantGames: []game{{"JP1_1", -25}},
+			wantLoss:  25,
+		},
+		{
+			name: "ディビジョンの昇格と降格",
+			snapshots: []riot.LeagueSnapshot{
+				lpSnapshot(0, "SILVER", "II", 90, 10, 10),
+				lpSnapshot(1, "SILVER", "I", 10, 11, 10),
+				lpSnapshot(2, "SILVER", "II", 95, 11, 11),
+			},
+			matches: []riot.MatchDetail{
+				lpMatch(1, 30, riot.QueueSoloRanked, true),
+				lpMatch(2, 90, riot.QueueSoloRanked, false),
+			},
+			wantNet:   5,
+			wantGames: []game{{"JP1_2", -15}, {"JP1_1", 20}},
+			wantGain:  20,
+			wantLoss:  15,
+		},
+		{
+			// 勝敗数が変わらない区間（ディケイなど）は試合に割り当てない
+			name: "スナップショット間に試合なし",
+			snapshots: []riot.LeagueSnapshot{
+				lpSnapshot(0, "DIAMOND", "IV", 50, 10, 10),
+				lpSnapshot(1, "DIAMOND", "IV", 25, 10, 10),
+			},
+			matches:   []riot.MatchDetail{lpMatch(1, 30, riot.QueueSoloRanked, true)},
+			wantNet:   -25,
+			wantGames: []game{},
+		},
+		{
+			// 勝ちのみ・負けのみの区間の平均（+20 / -15）の比で按分する
+			name: "勝敗が混在する区間",
+			snapshots: []riot.LeagueSnapshot{
+				lpSnapshot(0, "GOLD", "IV", 0, 10, 10),
+				lpSnapshot(1, "GOLD", "IV", 20, 11, 10),
+				lpSnapshot(2, "GOLD", "IV", 5, 11, 11),
+				lpSnapshot(3, "GOLD", "IV", 55, 13, 12),
+			},
+			matches: []riot.MatchDetail{
+				lpMatch(1, 30, riot.QueueSoloRanked, true),
+				lpMatch(2, 90, riot.QueueSoloRanked, false),
+				lpMatch(3, 130, riot.QueueSoloRanked, true),
+				lpMatch(4, 140, riot.QueueSoloRanked, false),
+				lpMatch(5, 150, riot.QueueSoloRanked, true),
+				// 別のキュー・区間外の試合は含めない
+				lpMatch(6, 160, riot.QueueFlexRanked, true),
+				lpMatch(7, 300, riot.QueueSoloRanked, true),
+			},
+			wantNet:   55,
+			wantGames: []game{{"JP1_5", 40}, {"JP1_4", -30}, {"JP1_3", 40}, {"JP1_2", -15}, {"JP1_1", 20}},
+			wantGain:  (20 + 40*2) / 3.0,
+			wantLoss:  (15 + 30) / 2.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := &riot.PlayerMatchSummary{
+				Account:       riot.Account{PUUID: fixturePUUID},
+				MatchHistory:  tt.matches,
+				LeagueHistory: tt.snapshots,
+			}
+
+			report := CalculateLPHistory(summary)
+			if report == nil || len(report.Queues) != 1 {
+				t.Fatalf("report = %+v, want 1 queue", report)
+			}
+			history := report.Queues[0]
+
+			if len(history.Series) != len(tt.snapshots) {
+				t.Errorf("Series = %d点, want %d", len(history.Series), len(tt.snapshots))
+			}
+			if history.NetLPChange != tt.wantNet {
+				t.Errorf("NetLPChange = %d, want %d", history.NetLPChange, tt.wantNet)
+			}
+
+			if len(history.Games) != len(tt.wantGames) {
+				t.Fatalf("Games = %+v, want %+v", history.Games, tt.wantGames)
+			}
+			for i, want := range tt.wantGames {
+				got := history.Games[i]
+				if got.MatchID != want.matchID || !approxEqual(got.EstimatedLPChange, want.change) {
+					t.Errorf("Games[%d] = %s %v, want %s %v", i, got.MatchID, got.EstimatedLPChange, want.matchID, want.change)
+				}
+			}
+
+			if !approxEqual(history.AverageLPGain, tt.wantGain) || !approxEqual(history.AverageLPLoss, tt.wantLoss) {
+				t.Errorf("AverageLPGain/Loss = %v/%v, want %v/%v", history.AverageLPGain, history.AverageLPLoss, tt.wantGain, tt.wantLoss)
+			}
+		})
+	}
+}
+
+func TestCalculateLPHistoryNoSnapshot(t *testing.T) {
+	summary := &riot.PlayerMatchSummary{Account: riot.Account{PUUID: fixturePUUID}}
+	if report := CalculateLPHistory(summary); report != nil {
+		t.Errorf("report = %+v, want nil", report)
+	}
+}
+
+func TestEstimateWindow(t *testing.T) {
+	tests := []struct {
+		name               string
+		wins, losses       int
+		change             int
+		baseGain, baseLoss float64
+		wantGain, wantLoss float64
+		wantOK             bool
+	}{
+		{name: "勝ちのみ", wins: 2, change: 44, wantGain: 22, wantOK: true},
+		{name: "負けのみ", losses: 2, change: -36, wantLoss: 18, wantOK: true},
+		{name: "平均の比で按分", wins: 2, losses: 1, change: 50, baseGain: 20, baseLoss: 15, wantGain: 40, wantLoss: 30, wantOK: true},
+		{name: "獲得のみ分かっている", wins: 2, losses: 1, change: 20, baseGain: 20, wantGain: 20, wantLoss: 20, wantOK: true},
+		{name: "損失のみ分かっている", wins: 2, losses: 1, change: 20, baseLoss: 10, wantGain: 15, wantLoss: 10, wantOK: true},
+		{name: "比が不明", wins: 3, losses: 1, change: 40, wantGain: 20, wantLoss: 20, wantOK: true},
+		{name: "勝敗が同数で比が不明", wins: 1, losses: 1, change: 5},
+		{name: "変化量と勝敗が矛盾", wins: 1, losses: 3, change: 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := lpWindow{wins: tt.wins, losses: tt.losses, change: tt.change}
+			gain, loss, ok := estimateWindow(w, tt.baseGain, tt.baseLoss)
+			if ok != tt.wantOK || !approxEqual(gain, tt.wantGain) || !approxEqual(loss, tt.wantLoss) {
+				t.Errorf("estimateWindow = %v, %v, %v, want %v, %v, %v", gain, loss, ok, tt.wantGain, tt.wantLoss, tt.wantOK)
+			}
+		})
+	}
+}
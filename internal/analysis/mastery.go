@@ -0,0 +1,134 @@
+package analysis
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+// マスタリーと直近成績の比較に使う基準
+const (
+	masteryMinRecentGames  = 3      // 判定に必要な直近の試合数
+	masteryHighPoints      = 100000 // これ以上を「使い込んだチャンピオン」とみなす
+	masteryNewPoints       = 30000  // これ未満を「新しいチャンピオン」とみなす
+	masteryStrugglingRate  = 45.0   // 使い込んだチャンピオンで不調とみなす勝率（%）
+	masteryPromisingRate   = 60.0   // 新しいチャンピオンで好調とみなす勝率（%）
+	masteryStrugglingRatio = 2.0    // 使い込んだチャンピオンで不調とみなすKDA
+)
+
+// 判定結果
+const (
+	MasteryStruggling = "struggling" // マスタリーが高いのに直近不調
+	MasteryPromising  = "promising"  // マスタリーが低いのに直近好調
+)
+
+// チャンピオンごとのマスタリーと直近成績
+type ChampionMasteryStats struct {
+	ChampionID    int       `json:"championId"`
+	ChampionName  string    `json:"championName"`
+	MasteryLevel  int       `json:"masteryLevel"`
+	MasteryPoints int       `json:"masteryPoints"`
+	LastPlayTime  time.Time `json:"lastPlayTime"`
+	RecentGames   int       `json:"recentGames"`
+	RecentWinRate float64   `json:"recentWinRate"`
+	RecentKDA     float64   `json:"recentKDA"`
+	Flag          string    `json:"flag,omitempty"` // struggling / promising
+}
+
+// マスタリーと直近成績の比較
+type MasteryReport struct {
+	Champions  []ChampionMasteryStats `json:"champions"`  // 直近プレイしたチャンピオン（マスタリーポイントの多い順）
+	Struggling []ChampionMasteryStats `json:"struggling"` // 使い込んでいるが直近不調
+	Promising  []ChampionMasteryStats `json:"promising"`  // 新しいが直近好調
+}
+
+// 直近の試合とマスタリーを突き合わせる（マスタリー未取得の場合は nil）
+func CalculateMastery(summary *riot.PlayerMatchSummary) *MasteryReport {
+	if len(summary.ChampionMasteries) == 0 {
+		return nil
+	}
+
+	type recentTotals struct {
+		name                   string
+		games, wins            int
+		kills, deaths, assists int
+	}
+
+	recent := make(map[int]*recentTotals)
+	for i := range summary.MatchHistory {
+		player := riot.FindParticipant(&summary.MatchHistory[i], summary.Account.PUUID)
+		if player == nil {
+			continue
+		}
+
+		totals := recent[player.ChampionID]
+		if totals == nil {
+			totals = &recentTotals{name: player.ChampionName}
+			recent[player.ChampionID] = totals
+		}
+		totals.games++
+		if player.Win {
+			totals.wins++
+		}
+		totals.kills += player.Kills
+		totals.deaths += player.Deaths
+		totals.assists += player.Assists
+	}
+
+	report := &MasteryReport{
+		Champions:  []ChampionMasteryStats{},
+		Struggling: []ChampionMasteryStats{},
+		Promising:  []ChampionMasteryStats{},
+	}
+
+	masteries := make(map[int]riot.ChampionMastery, len(summary.ChampionMasteries))
+	for _, mastery := range summary.ChampionMasteries {
+		masteries[mastery.ChampionID] = mastery
+	}
+
+	for championID, totals := range recent {
+		// マスタリーがない場合は初プレイ（ポイント0）として扱う
+		mastery := masteries[championID]
+
+		stats := ChampionMasteryStats{
+			ChampionID:    championID,
+			ChampionName:  totals.name,
+			MasteryLevel:  mastery.ChampionLevel,
+			MasteryPoints: mastery.ChampionPoints,
+			RecentGames:   totals.games,
+			RecentWinRate: float64(totals.wins) / float64(totals.games) * 100,
+			RecentKDA:     kdaRatio(totals.kills, totals.deaths, totals.assists, totals.games),
+		}
+		if mastery.LastPlayTime > 0 {
+			stats.LastPlayTime = mastery.LastPlayed()
+		}
+
+		if stats.RecentGames >= masteryMinRecentGames {
+			switch {
+			case stats.MasteryPoints >= masteryHighPoints &&
+				(stats.RecentWinRate < masteryStrugglingRate || stats.RecentKDA < masteryStrugglingRatio):
+				stats.Flag = MasteryStruggling
+				report.Struggling = append(report.Struggling, stats)
+			case stats.MasteryPoints < masteryNewPoints && stats.RecentWinRate >= masteryPromisingRate:
+				stats.Flag = MasteryPromising
+				report.Promising = append(report.Promising, stats)
+			}
+		}
+
+		report.Champions = append(report.Champions, stats)
+	}
+
+	byPoints := func(a, b ChampionMasteryStats) int {
+		if c := cmp.Compare(b.MasteryPoints, a.MasteryPoints); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ChampionID, b.ChampionID)
+	}
+	slices.SortFunc(report.Champions, byPoints)
+	slices.SortFunc(report.Struggling, byPoints)
+	slices.SortFunc(report.Promising, byPoints)
+
+	return report
+}
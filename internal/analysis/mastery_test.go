@@ -0,0 +1,82 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+func TestCalculateMasteryFlags(t *testing.T) {
+	const championID = 103
+
+	// 1試合あたりの KDA は kills / deaths / assists で固定
+	tests := []struct {
+		name                   string
+		points                 int // -1 でマスタリーなし
+		wins, losses           int
+		kills, deaths, assists int
+		want                   string
+	}{
+		{name: "使い込んだチャンピオンで勝率が低い", points: 100000, wins: 1, losses: 2, kills: 5, deaths: 2, assists: 5, want: MasteryStruggling},
+		{name: "使い込んだチャンピオンで勝率が基準ちょうど", points: 100000, wins: 9, losses: 11, kills: 5, deaths: 2, assists: 5},
+		{name: "使い込んだチャンピオンでKDAが低い", points: 250000, wins: 3, losses: 0, kills: 1, deaths: 2, assists: 2, want: MasteryStruggling},
+		{name: "使い込んだチャンピオンでKDAが基準ちょうど", points: 250000, wins: 3, losses: 0, kills: 1, deaths: 2, assists: 3},
+		{name: "使い込んだとみなす基準未満", points: 99999, wins: 0, losses: 3, kills: 0, deaths: 5, assists: 0},
+		{name: "直近の試合数が基準未満", points: 100000, wins: 0, losses: 2, kills: 0, deaths: 5, assists: 0},
+		{name: "新しいチャンピオンで勝率が基準ちょうど", points: 29999, wins: 3, losses: 2, kills: 1, deaths: 5, assists: 1, want: MasteryPromising},
+		{name: "新しいチャンピオンで勝率が基準未満", points: 29999, wins: 2, losses: 2, kills: 10, deaths: 0, assists: 10},
+		{name: "新しいとみなす基準ちょうど", points: 30000, wins: 3, losses: 0, kills: 10, deaths: 0, assists: 10},
+		{name: "マスタリーなしは新しいチャンピオン", points: -1, wins: 3, losses: 0, kills: 5, deaths: 2, assists: 5, want: MasteryPromising},
+		{name: "中間のマスタリー", points: 50000, wins: 0, losses: 3, kills: 0, deaths: 5, assists: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 判定対象以外のマスタリーも含める（マスタリー未取得と区別するため）
+			summary := &riot.PlayerMatchSummary{
+				Account:           riot.Account{PUUID: fixturePUUID},
+				ChampionMasteries: []riot.ChampionMastery{{ChampionID: 1, ChampionPoints: 500000}},
+			}
+			if tt.points >= 0 {
+				summary.ChampionMasteries = append(summary.ChampionMasteries,
+					riot.ChampionMastery{ChampionID: championID, ChampionPoints: tt.points})
+			}
+			for i := range tt.wins + tt.losses {
+				var match riot.MatchDetail
+				match.Info.Participants = []riot.Participant{{
+					PUUID: fixturePUUID, ChampionID: championID, ChampionName: "Ahri", Win: i < tt.wins,
+					Kills: tt.kills, Deaths: tt.deaths, Assists: tt.assists,
+				}}
+				summary.MatchHistory = append(summary.MatchHistory, match)
+			}
+
+			report := CalculateMastery(summary)
+			if report == nil || len(report.Champions) != 1 {
+				t.Fatalf("report = %+v, want 1 champion", report)
+			}
+			if got := report.Champions[0].Flag; got != tt.want {
+				t.Errorf("Flag = %q, want %q (winRate %v, KDA %v)",
+					got, tt.want, report.Champions[0].RecentWinRate, report.Champions[0].RecentKDA)
+			}
+
+			wantStruggling, wantPromising := 0, 0
+			switch tt.want {
+			case MasteryStruggling:
+				wantStruggling = 1
+			case MasteryPromising:
+				wantPromising = 1
+			}
+			if len(report.Struggling) != wantStruggling || len(report.Promising) != wantPromising {
+				t.Errorf("Struggling = %d, Promising = %d, want %d, %d",
+					len(report.Struggling), len(report.Promising), wantStruggling, wantPromising)
+			}
+		})
+	}
+}
+
+func TestCalculateMasteryNoMastery(t *testing.T) {
+	summary := &riot.PlayerMatchSummary{Account: riot.Account{PUUID: fixturePUUID}}
+	if report := CalculateMastery(summary); report != nil {
+		t.Errorf("report = %+v, want nil", report)
+	}
+}
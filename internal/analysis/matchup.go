@@ -0,0 +1,171 @@
+package analysis
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+// 得意・苦手な対面の判定基準
+const (
+	matchupMinGames  = 2 // 判定に必要な試合数
+	matchupListLimit = 3 // チャンピオンごとに表示する得意・苦手な対面の数
+)
+
+// 自分のチャンピオンと対面（同じ TeamPosition の敵）のチャンピオンの組み合わせごとの成績
+// 差分は試合終了時の対面との比較で、プラスが有利
+type MatchupStats struct {
+	ChampionName     string   `json:"championName"`
+	OpponentChampion string   `json:"opponentChampion"`
+	Games            int      `json:"games"`
+	Wins             int      `json:"wins"`
+	WinRate          float64  `json:"winRate"`
+	AverageKDA       KDAStats `json:"averageKDA"`
+	AverageGoldDiff  float64  `json:"averageGoldDiff"`
+	AverageCSDiff    float64  `json:"averageCSDiff"`
+}
+
+// 自分のチャンピオンごとの対面成績
+type ChampionMatchups struct {
+	ChampionName string         `json:"championName"`
+	Games        int            `json:"games"`
+	Matchups     []MatchupStats `json:"matchups"` // 試合数の多い順
+	Best         []MatchupStats `json:"best"`     // 得意な対面（勝率・ゴールド差の高い順）
+	Worst        []MatchupStats `json:"worst"`    // 苦手な対面（勝率・ゴールド差の低い順）
+}
+
+// 対面との成績のレポート
+type MatchupReport struct {
+	Matchups       []MatchupStats     `json:"matchups"`       // 全組み合わせ（試合数の多い順）
+	ByChampion     []ChampionMatchups `json:"byChampion"`     // 試合数の多い順
+	SkippedMatches int                `json:"skippedMatches"` // 対面が見つからず集計できなかった試合数（ARAM など）
+}
+
+// 分析対象の試合から対面との成績のレポートを作成
+func CalculateMatchups(summary *riot.PlayerMatchSummary) *MatchupReport {
+	report := &MatchupReport{
+		Matchups:   []MatchupStats{},
+		ByChampion: []ChampionMatchups{},
+	}
+
+	type matchupKey struct{ champion, opponent string }
+	totals := make(map[matchupKey]*matchupTotals)
+
+	for i := range summary.MatchHistory {
+		player, opponent := laneOpponents(&summary.MatchHistory[i], summary.Account.PUUID)
+		if player == nil {
+			continue
+		}
+		if opponent == nil {
+			report.SkippedMatches++
+			continue
+		}
+
+		key := matchupKey{player.ChampionName, opponent.ChampionName}
+		if totals[key] == nil {
+			totals[key] = &matchupTotals{}
+		}
+		totals[key].add(player, opponent)
+	}
+
+	champions := make(map[string]*ChampionMatchups)
+	for key, t := range totals {
+		matchup := t.stats(key.champion, key.opponent)
+		report.Matchups = append(report.Matchups, matchup)
+
+		if champions[key.champion] == nil {
+			champions[key.champion] = &ChampionMatchups{ChampionName: key.champion}
+		}
+		champions[key.champion].Games += matchup.Games
+		champions[key.champion].Matchups = append(champions[key.champion].Matchups, matchup)
+	}
+
+	slices.SortFunc(report.Matchups, byMatchupGames)
+
+	for _, champion := range champions {
+		slices.SortFunc(champion.Matchups, byMatchupGames)
+		champion.Best, champion.Worst = bestAndWorstMatchups(champion.Matchups)
+		report.ByChampion = append(report.ByChampion, *champion)
+	}
+
+	slices.SortFunc(report.ByChampion, func(a, b ChampionMatchups) int {
+		if c := cmp.Compare(b.Games, a.Games); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ChampionName, b.ChampionName)
+	})
+
+	return report
+}
+
+// 試合数が判定基準以上の対面から得意・苦手な対面を選ぶ（同じ対面が両方に入らないよう、最大で半数ずつ）
+func bestAndWorstMatchups(matchups []MatchupStats) (best, worst []MatchupStats) {
+	var qualified []MatchupStats
+	for _, matchup := range matchups {
+		if matchup.Games >= matchupMinGames {
+			qualified = append(qualified, matchup)
+		}
+	}
+
+	// 勝率、同じ場合は試合終了時のゴールド差の高い順
+	slices.SortFunc(qualified, func(a, b MatchupStats) int {
+		if c := cmp.Compare(b.WinRate, a.WinRate); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.AverageGoldDiff, a.AverageGoldDiff); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.OpponentChampion, b.OpponentChampion)
+	})
+
+	n := min(matchupListLimit, len(qualified)/2)
+	best = append([]MatchupStats{}, qualified[:n]...)
+	worst = append([]MatchupStats{}, qualified[len(qualified)-n:]...)
+	slices.Reverse(worst)
+
+	return best, worst
+}
+
+func byMatchupGames(a, b MatchupStats) int {
+	if c := cmp.Compare(b.Games, a.Games); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.ChampionName, b.ChampionName); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.OpponentChampion, b.OpponentChampion)
+}
+
+type matchupTotals struct {
+	games, wins            int
+	kills, deaths, assists int
+	goldDiff, csDiff       int
+}
+
+func (t *matchupTotals) add(player, opponent *riot.Participant) {
+	t.games++
+	if player.Win {
+		t.wins++
+	}
+	t.kills += player.Kills
+	t.deaths += player.Deaths
+	t.assists += player.Assists
+	t.goldDiff += player.GoldEarned - opponent.GoldEarned
+	t.csDiff += (player.TotalMinionsKilled + player.NeutralMinionsKilled) -
+		(opponent.TotalMinionsKilled + opponent.NeutralMinionsKilled)
+}
+
+func (t *matchupTotals) stats(champion, opponent string) MatchupStats {
+	games := float64(t.games)
+	return MatchupStats{
+		ChampionName:     champion,
+		OpponentChampion: opponent,
+		Games:            t.games,
+		Wins:             t.wins,
+		WinRate:          float64(t.wins) / games * 100,
+		AverageKDA:       AverageKDA(t.kills, t.deaths, t.assists, t.games),
+		AverageGoldDiff:  float64(t.goldDiff) / games,
+		AverageCSDiff:    float64(t.csDiff) / games,
+	}
+}
@@ -0,0 +1,168 @@
+package analysis
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+// 対面の試合（プレイヤーは青チーム、対面は赤チームの同じポジション）
+type laneGame struct {
+	champion, opponent string
+	position           string // 空の場合は MIDDLE、"-" でポジションなし（ARAM など）
+	win                bool
+	goldDiff           int
+}
+
+func laneMatch(g laneGame) riot.MatchDetail {
+	position := g.position
+	switch position {
+	case "":
+		position = "MIDDLE"
+	case "-":
+		position = ""
+	}
+
+	var match riot.MatchDetail
+	match.Info.Participants = []riot.Participant{
+		{PUUID: fixturePUUID, TeamID: 100, ChampionName: g.champion, TeamPosition: position, Win: g.win,
+			Kills: 4, Deaths: 2, Assists: 6, GoldEarned: 10000 + g.goldDiff, TotalMinionsKilled: 200},
+		{PUUID: "opponent", TeamID: 200, ChampionName: g.opponent, TeamPosition: position, Win: !g.win,
+			GoldEarned: 10000, TotalMinionsKilled: 190},
+	}
+	return match
+}
+
+func laneSummary(games []laneGame) *riot.PlayerMatchSummary {
+	summary := &riot.PlayerMatchSummary{Account: riot.Account{PUUID: fixturePUUID}}
+	for _, g := range games {
+		summary.MatchHistory = append(summary.MatchHistory, laneMatch(g))
+	}
+	return summary
+}
+
+func TestCalculateMatchups(t *testing.T) {
+	summary := laneSummary([]laneGame{
+		{champion: "Ahri", opponent: "Zed", win: true, goldDiff: 500},
+		{champion: "Ahri", opponent: "Zed", win: false, goldDiff: -300},
+		{champion: "Ahri", opponent: "Yasuo", win: true, goldDiff: 100},
+		{champion: "Jinx", opponent: "Ezreal", position: "BOTTOM", win: false, goldDiff: -200},
+		{champion: "Ahri", opponent: "Lux", position: "-", win: true},
+	})
+
+	report := CalculateMatchups(summary)
+
+	if report.SkippedMatches != 1 {
+		t.Errorf("SkippedMatches = %d, want 1", report.SkippedMatches)
+	}
+
+	// 試合数の多い順、同数は自分・対面のチャンピオン名順
+	type matchup struct {
+		champion, opponent string
+		games, wins        int
+		goldDiff, csDiff   float64
+	}
+	want := []matchup{
+		{"Ahri", "Zed", 2, 1, 100, 10},
+		{"Ahri", "Yasuo", 1, 1, 100, 10},
+		{"Jinx", "Ezreal", 1, 0, -200, 10},
+	}
+
+	var got []matchup
+	for _, m := range report.Matchups {
+		got = append(got, matchup{m.ChampionName, m.OpponentChampion, m.Games, m.Wins, m.AverageGoldDiff, m.AverageCSDiff})
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Matchups = %+v, want %+v", got, want)
+	}
+
+	var champions []string
+	for _, c := range report.ByChampion {
+		champions = append(champions, c.ChampionName)
+	}
+	if want := []string{"Ahri", "Jinx"}; !slices.Equal(champions, want) {
+		t.Errorf("ByChampion = %v, want %v", champions, want)
+	}
+	if games := report.ByChampion[0].Games; games != 3 {
+		t.Errorf("Ahri の試合数 = %d, want 3", games)
+	}
+}
+
+func TestBestAndWorstMatchups(t *testing.T) {
+	matchup := func(opponent string, games int, winRate, goldDiff float64) MatchupStats {
+		return MatchupStats{ChampionName: "Ahri", OpponentChampion: opponent, Games: games, WinRate: winRate, AverageGoldDiff: goldDiff}
+	}
+
+	tests := []struct {
+		name      string
+		matchups  []MatchupStats
+		wantBest  []string
+		wantWorst []string
+	}{
+		{
+			name:      "対面なし",
+			wantBest:  []string{},
+			wantWorst: []string{},
+		},
+		{
+			name:      "試合数が判定基準未満",
+			matchups:  []MatchupStats{matchup("Zed", 1, 100, 0), matchup("Yasuo", 1, 0, 0)},
+			wantBest:  []string{},
+			wantWorst: []string{},
+		},
+		{
+			// 1つだけでは得意・苦手の両方に入るため選ばない
+			name:      "判定基準を満たす対面が1つ",
+			matchups:  []MatchupStats{matchup("Zed", 2, 100, 0), matchup("Yasuo", 1, 0, 0)},
+			wantBest:  []string{},
+			wantWorst: []string{},
+		},
+		{
+			name:      "2つ",
+			matchups:  []MatchupStats{matchup("Zed", 2, 0, 0), matchup("Yasuo", 3, 66.7, 0)},
+			wantBest:  []string{"Yasuo"},
+			wantWorst: []string{"Zed"},
+		},
+		{
+			name: "同じ勝率はゴールド差、同じ場合は名前順",
+			matchups: []MatchupStats{
+				matchup("Zed", 2, 50, 100),
+				matchup("Yasuo", 2, 50, 300),
+				matchup("Akali", 2, 50, 100),
+				matchup("Lux", 2, 50, -200),
+			},
+			wantBest:  []string{"Yasuo", "Akali"},
+			wantWorst: []string{"Lux", "Zed"},
+		},
+		{
+			name: "最大3つずつ",
+			matchups: []MatchupStats{
+				matchup("A", 2, 100, 0), matchup("B", 2, 90, 0), matchup("C", 2, 80, 0), matchup("D", 2, 70, 0),
+				matchup("E", 2, 60, 0), matchup("F", 2, 50, 0), matchup("G", 2, 40, 0), matchup("H", 2, 30, 0),
+			},
+			wantBest:  []string{"A", "B", "C"},
+			wantWorst: []string{"H", "G", "F"},
+		},
+	}
+
+	opponents := func(matchups []MatchupStats) []string {
+		names := []string{}
+		for _, m := range matchups {
+			names = append(names, m.OpponentChampion)
+		}
+		return names
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			best, worst := bestAndWorstMatchups(tt.matchups)
+			if got := opponents(best); !slices.Equal(got, tt.wantBest) {
+				t.Errorf("best = %v, want %v", got, tt.wantBest)
+			}
+			if got := opponents(worst); !slices.Equal(got, tt.wantWorst) {
+				t.Errorf("worst = %v, want %v", got, tt.wantWorst)
+			}
+		})
+	}
+}
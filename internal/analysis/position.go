@@ -0,0 +1,67 @@
+package analysis
+
+import (
+	"slices"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+// ポジションごとに表示するチャンピオン数
+const positionTopChampions = 3
+
+// 集計対象のポジション（ARAM などでは TeamPosition が空、判定できない試合では "Invalid" などになる）
+var teamPositions = []string{"TOP", "JUNGLE", "MIDDLE", "BOTTOM", "UTILITY"}
+
+func isTeamPosition(position string) bool {
+	return slices.Contains(teamPositions, position)
+}
+
+// ポジション（TeamPosition）ごとの成績（1分あたりの値は試合時間の合計で割った値）
+type PositionPerformance struct {
+	Games           int             `json:"games"`
+	Wins            int             `json:"wins"`
+	WinRate         float64         `json:"winRate"`
+	AverageKDA      KDAStats        `json:"averageKDA"`
+	CSPerMinute     float64         `json:"csPerMinute"`
+	VisionPerMinute float64         `json:"visionPerMinute"`
+	DamagePerMinute float64         `json:"damagePerMinute"` // チャンピオンへのダメージ
+	GoldPerMinute   float64         `json:"goldPerMinute"`
+	TopChampions    []ChampionStats `json:"topChampions"` // そのポジションで試合数の多いチャンピオン
+}
+
+type positionTotals struct {
+	statTotals
+	champions map[string]*statTotals
+}
+
+func newPositionTotals() *positionTotals {
+	return &positionTotals{champions: make(map[string]*statTotals)}
+}
+
+func (t *positionTotals) add(match *riot.MatchDetail, player *riot.Participant) {
+	t.statTotals.add(match, player)
+
+	if t.champions[player.ChampionName] == nil {
+		t.champions[player.ChampionName] = &statTotals{}
+	}
+	t.champions[player.ChampionName].add(match, player)
+}
+
+func (t *positionTotals) performance() PositionPerformance {
+	champions := championStatsList(t.champions)
+	if len(champions) > positionTopChampions {
+		champions = champions[:positionTopChampions]
+	}
+
+	return PositionPerformance{
+		Games:           t.games,
+		Wins:            t.wins,
+		WinRate:         t.winRate(),
+		AverageKDA:      t.kda(),
+		CSPerMinute:     t.perMinute(t.cs),
+		VisionPerMinute: t.perMinute(t.visionScore),
+		DamagePerMinute: t.perMinute(t.damage),
+		GoldPerMinute:   t.perMinute(t.goldEarned),
+		TopChampions:    champions,
+	}
+}
@@ -0,0 +1,166 @@
+package analysis
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+// ポジション別の集計用の試合（値は1試合分の合計）
+type positionGame struct {
+	position string
+	champion string
+	win      bool
+	minutes  int
+	cs       int
+	vision   int
+	damage   int
+	gold     int
+}
+
+func positionMatch(g positionGame) riot.MatchDetail {
+	var match riot.MatchDetail
+	match.Info.GameDuration = g.minutes * 60
+	match.Info.Participants = []riot.Participant{{
+		PUUID:                       fixturePUUID,
+		TeamID:                      100,
+		TeamPosition:                g.position,
+		ChampionName:                g.champion,
+		Win:                         g.win,
+		Kills:                       3,
+		Deaths:                      2,
+		Assists:                     5,
+		TotalMinionsKilled:          g.cs,
+		VisionScore:                 g.vision,
+		TotalDamageDealtToChampions: g.damage,
+		GoldEarned:                  g.gold,
+	}}
+	return match
+}
+
+func TestPositionPerformance(t *testing.T) {
+	type performance struct {
+		games, wins int
+		winRate     float64
+		cs, vision  float64 // 1分あたり
+		damage      float64
+		gold        float64
+		champions   []string
+	}
+
+	tests := []struct {
+		name        string
+		games       []positionGame
+		wantMatches int
+		want        map[string]performance
+	}{
+		{
+			name: "1分あたりは試合時間の合計で割る",
+			games: []positionGame{
+				{position: "MIDDLE", champion: "Ahri", win: true, minutes: 20, cs: 160, vision: 20, damage: 20000, gold: 10000},
+				{position: "MIDDLE", champion: "Ahri", win: false, minutes: 40, cs: 320, vision: 40, damage: 40000, gold: 14000},
+			},
+			wantMatches: 2,
+			want: map[string]performance{
+				"MIDDLE": {games: 2, wins: 1, winRate: 50, cs: 8, vision: 1, damage: 1000, gold: 400, champions: []string{"Ahri"}},
+			},
+		},
+		{
+			name: "ポジションごとに集計",
+			games: []positionGame{
+				{position: "BOTTOM", champion: "Jinx", win: true, minutes: 30, cs: 270},
+				{position: "UTILITY", champion: "Lulu", win: false, minutes: 30, vision: 90},
+			},
+			wantMatches: 2,
+			want: map[string]performance{
+				"BOTTOM":  {games: 1, wins: 1, winRate: 100, cs: 9, champions: []string{"Jinx"}},
+				"UTILITY": {games: 1, winRate: 0, vision: 3, champions: []string{"Lulu"}},
+			},
+		},
+		{
+			// 全体の成績には含めるがポジション別には含めない
+			name: "ポジションが空・不正な試合",
+			games: []positionGame{
+				{position: "TOP", champion: "Garen", win: true, minutes: 30, cs: 210},
+				{position: "", champion: "Ahri", win: true, minutes: 20, cs: 60},
+				{position: "Invalid", champion: "Jinx", win: false, minutes: 25, cs: 100},
+			},
+			wantMatches: 3,
+			want: map[string]performance{
+				"TOP": {games: 1, wins: 1, winRate: 100, cs: 7, champions: []string{"Garen"}},
+			},
+		},
+		{
+			name: "試合数の多いチャンピオン3体",
+			games: []positionGame{
+				{position: "JUNGLE", champion: "LeeSin", minutes: 30},
+				{position: "JUNGLE", champion: "Viego", minutes: 30},
+				{position: "JUNGLE", champion: "Viego", minutes: 30},
+				{position: "JUNGLE", champion: "Hecarim", minutes: 30},
+				{position: "JUNGLE", champion: "Elise", minutes: 30},
+			},
+			wantMatches: 5,
+			want: map[string]performance{
+				"JUNGLE": {games: 5, champions: []string{"Viego", "Elise", "Hecarim"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := &riot.PlayerMatchSummary{Account: riot.Account{PUUID: fixturePUUID}}
+			for _, g := range tt.games {
+				summary.MatchHistory = append(summary.MatchHistory, positionMatch(g))
+			}
+
+			stats := CalculatePlayerStats(summary)
+			if stats.TotalMatches != tt.wantMatches {
+				t.Errorf("TotalMatches = %d, want %d", stats.TotalMatches, tt.wantMatches)
+			}
+
+			positions := slices.Sorted(maps.Keys(stats.PositionPerformance))
+			if want := slices.Sorted(maps.Keys(tt.want)); !slices.Equal(positions, want) {
+				t.Fatalf("PositionPerformance のポジション = %v, want %v", positions, want)
+			}
+			if got := slices.Sorted(maps.Keys(stats.PositionStats)); !slices.Equal(got, positions) {
+				t.Errorf("PositionStats のポジション = %v, want %v", got, positions)
+			}
+			if got := slices.Sorted(maps.Keys(stats.Contribution.ByPosition)); !slices.Equal(got, positions) {
+				t.Errorf("Contribution.ByPosition のポジション = %v, want %v", got, positions)
+			}
+
+			for position, want := range tt.want {
+				got := stats.PositionPerformance[position]
+				if got.Games != want.games || got.Wins != want.wins || stats.PositionStats[position] != want.games {
+					t.Errorf("%s: games = %d (PositionStats %d), wins = %d, want %d, %d",
+						position, got.Games, stats.PositionStats[position], got.Wins, want.games, want.wins)
+				}
+
+				for _, c := range []struct {
+					label     string
+					got, want float64
+				}{
+					{"WinRate", got.WinRate, want.winRate},
+					{"CSPerMinute", got.CSPerMinute, want.cs},
+					{"VisionPerMinute", got.VisionPerMinute, want.vision},
+					{"DamagePerMinute", got.DamagePerMinute, want.damage},
+					{"GoldPerMinute", got.GoldPerMinute, want.gold},
+				} {
+					if !approxEqual(c.got, c.want) {
+						t.Errorf("%s: %s = %v, want %v", position, c.label, c.got, c.want)
+					}
+				}
+
+				var champions []string
+				for _, champion := range got.TopChampions {
+					champions = append(champions, champion.ChampionName)
+				}
+				if !slices.Equal(champions, want.champions) {
+					t.Errorf("%s: TopChampions = %v, want %v", position, champions, want.champions)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package analysis
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+// 直近の調子に使う試合数
+const (
+	recentFormLongGames  = 10
+	recentFormShortGames = 5
+)
+
+// プレイヤーの統計（CLI の統計ファイルとサーバーのレスポンスで共通）
+type PlayerStats struct {
+	PlayerInfo          riot.Account                   `json:"playerInfo"`
+	GeneratedAt         time.Time                      `json:"generatedAt"`
+	MatchType           string                         `json:"matchType"`
+	TotalMatches        int                            `json:"totalMatches"` // プレイヤーが見つかった試合数
+	WinRate             float64                        `json:"winRate"`
+	Rank                RankInfo                       `json:"rank"`
+	AverageKDA          KDAStats                       `json:"averageKDA"`
+	RankPerformance     RankStats                      `json:"rankPerformance"`
+	MostPlayedChampions []ChampionStats                `json:"mostPlayedChampions"` // 試合数の多い順
+	PositionStats       map[string]int                 `json:"positionStats"`       // TeamPosition ごとの試合数
+	PositionPerformance map[string]PositionPerformance `json:"positionPerformance"` // TeamPosition ごとの成績
+	RecentForm          RecentFormStats                `json:"recentForm"`          // 直近の調子
+
+	// キル関与率・ダメージ割合などチーム内での貢献度（全体・チャンピオン別・ポジション別）
+	Contribution *ContributionReport `json:"contribution"`
+
+	// 対面（同じ TeamPosition の敵）のチャンピオンとの組み合わせごとの成績
+	Matchups *MatchupReport `json:"matchups"`
+
+	// 複数の試合で同じチームだったチームメイトとの成績
+	Synergy *SynergyReport `json:"synergy"`
+
+	// LP の推移と1試合あたりの推定LP（ランク履歴がある場合のみ）
+	LPHistory *LPHistoryReport `json:"lpHistory,omitempty"`
+
+	// マスタリーと直近成績の比較（マスタリー取得時のみ）
+	Mastery *MasteryReport `json:"mastery,omitempty"`
+
+	// レーン戦の成績（タイムライン取得時のみ）
+	Laning *LaningReport `json:"laning,omitempty"`
+
+	// 試合ごとのバン・装備・ルーン・サモナースペルの名前とアイコン（静的データがある場合のみ）
+	Matches []MatchAssets `json:"matches,omitempty"`
+}
+
+// 現在のランク（未ランクのキューは nil）
+type RankInfo struct {
+	Solo *riot.LeagueEntry `json:"solo"`
+	Flex *riot.LeagueEntry `json:"flex"`
+}
+
+type RankStats struct {
+	AverageVisionScore float64 `json:"averageVisionScore"`
+	AverageGoldEarned  float64 `json:"averageGoldEarned"`
+	AverageCSPerMin    float64 `json:"averageCSPerMin"`
+	KillParticipation  float64 `json:"killParticipation"` // キル関与率（%、1試合ごとの値の平均）
+}
+
+// 1試合あたりの平均KDA（Ratio はデスが0の場合1試合あたりのキル+アシスト）
+type KDAStats struct {
+	Kills   float64 `json:"kills"`
+	Deaths  float64 `json:"deaths"`
+	Assists float64 `json:"assists"`
+	Ratio   float64 `json:"kdaRatio"`
+}
+
+type ChampionStats struct {
+	ChampionName string   `json:"championName"`
+	GamesPlayed  int      `json:"gamesPlayed"`
+	Wins         int      `json:"wins"`
+	WinRate      float64  `json:"winRate"`
+	AverageKDA   KDAStats `json:"averageKDA"`
+}
+
+type FormStats struct {
+	WinRate    float64  `json:"winRate"`
+	AverageKDA KDAStats `json:"averageKDA"`
+}
+
+type RecentFormStats struct {
+	Last10Games FormStats `json:"last10Games"`
+	Last5Games  FormStats `json:"last5Games"`
+}
+
+// キル・デス・アシストの合計から1試合あたりの平均KDAを計算
+func AverageKDA(kills, deaths, assists, games int) KDAStats {
+	if games == 0 {
+		return KDAStats{}
+	}
+
+	n := float64(games)
+	return KDAStats{
+		Kills:   float64(kills) / n,
+		Deaths:  float64(deaths) / n,
+		Assists: float64(assists) / n,
+		Ratio:   kdaRatio(kills, deaths, assists, games),
+	}
+}
+
+// KDA比率（デスが0の場合は1試合あたりのキル+アシスト）
+func kdaRatio(kills, deaths, assists, games int) float64 {
+	if deaths == 0 {
+		if games == 0 {
+			return 0
+		}
+		return float64(kills+assists) / float64(games)
+	}
+	return float64(kills+assists) / float64(deaths)
+}
+
+// 分析結果からプレイヤーの統計を計算
+// プレイヤーが参加していない試合（PUUID が見つからない試合）は集計に含めない
+func CalculatePlayerStats(summary *riot.PlayerMatchSummary) *PlayerStats {
+	stats := &PlayerStats{
+		PlayerInfo:          summary.Account,
+		GeneratedAt:         summary.GeneratedAt,
+		MatchType:           summary.MatchType,
+		MostPlayedChampions: []ChampionStats{},
+		PositionStats:       make(map[string]int),
+		PositionPerformance: make(map[string]PositionPerformance),
+		Rank: RankInfo{
+			Solo: riot.FindLeagueEntry(summary.LeagueEntries, riot.QueueTypeSolo),
+			Flex: riot.FindLeagueEntry(summary.LeagueEntries, riot.QueueTypeFlex),
+		},
+		LPHistory: CalculateLPHistory(summary),
+		Mastery:   CalculateMastery(summary),
+		Laning:    CalculateLaning(summary),
+
+		Contribution: CalculateContribution(summary),
+		Matchups:     CalculateMatchups(summary),
+		Synergy:      CalculateSynergy(summary),
+	}
+
+	var overall, last10, last5 statTotals
+	champions := make(map[string]*statTotals)
+	positions := make(map[string]*positionTotals)
+
+	// MatchHistory は新しい順
+	for i := range summary.MatchHistory {
+		match := &summary.MatchHistory[i]
+
+		player := riot.FindParticipant(match, summary.Account.PUUID)
+		if player == nil {
+			continue
+		}
+
+		if overall.games < recentFormLongGames {
+			last10.add(match, player)
+		}
+		if overall.games < recentFormShortGames {
+			last5.add(match, player)
+		}
+		overall.add(match, player)
+
+		if isTeamPosition(player.TeamPosition) {
+			stats.PositionStats[player.TeamPosition]++
+
+			if positions[player.TeamPosition] == nil {
+				positions[player.TeamPosition] = newPositionTotals()
+			}
+			positions[player.TeamPosition].add(match, player)
+		}
+
+		if champions[player.ChampionName] == nil {
+			champions[player.ChampionName] = &statTotals{}
+		}
+		champions[player.ChampionName].add(match, player)
+	}
+
+	if overall.games == 0 {
+		return stats
+	}
+
+	stats.TotalMatches = overall.games
+	stats.WinRate = overall.winRate()
+	stats.AverageKDA = overall.kda()
+	stats.RankPerformance = RankStats{
+		AverageVisionScore: overall.perGame(overall.visionScore),
+		AverageGoldEarned:  overall.perGame(overall.goldEarned),
+		AverageCSPerMin:    overall.perMinute(overall.cs),
+		KillParticipation:  stats.Contribution.Overall.AverageKillParticipation,
+	}
+	stats.RecentForm = RecentFormStats{
+		Last10Games: last10.form(),
+		Last5Games:  last5.form(),
+	}
+
+	stats.MostPlayedChampions = championStatsList(champions)
+
+	for position, totals := range positions {
+		stats.PositionPerformance[position] = totals.performance()
+	}
+
+	return stats
+}
+
+// チャンピオンごとの合計から成績の一覧を作成（試合数の多い順）
+func championStatsList(champions map[string]*statTotals) []ChampionStats {
+	list := make([]ChampionStats, 0, len(champions))
+	for name, totals := range champions {
+		list = append(list, ChampionStats{
+			ChampionName: name,
+			GamesPlayed:  totals.games,
+			Wins:         totals.wins,
+			WinRate:      totals.winRate(),
+			AverageKDA:   totals.kda(),
+		})
+	}
+
+	slices.SortFunc(list, func(a, b ChampionStats) int {
+		if c := cmp.Compare(b.GamesPlayed, a.GamesPlayed); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ChampionName, b.ChampionName)
+	})
+
+	return list
+}
+
+// 試合ごとの成績の合計
+type statTotals struct {
+	games, wins            int
+	kills, deaths, assists int
+	visionScore            int
+	goldEarned             int
+	cs                     int
+	damage                 int // チャンピオンへのダメージ
+	seconds                int // 試合時間の合計
+}
+
+func (t *statTotals) add(match *riot.MatchDetail, player *riot.Participant) {
+	t.games++
+	if player.Win {
+		t.wins++
+	}
+	t.kills += player.Kills
+	t.deaths += player.Deaths
+	t.assists += player.Assists
+	t.visionScore += player.VisionScore
+	t.goldEarned += player.GoldEarned
+	t.cs += player.TotalMinionsKilled + player.NeutralMinionsKilled
+	t.damage += player.TotalDamageDealtToChampions
+	t.seconds += match.Info.GameDuration
+}
+
+// 勝率（%）
+func (t *statTotals) winRate() float64 {
+	return t.perGame(t.wins) * 100
+}
+
+func (t *statTotals) kda() KDAStats {
+	return AverageKDA(t.kills, t.deaths, t.assists, t.games)
+}
+
+func (t *statTotals) form() FormStats {
+	return FormStats{
+		WinRate:    t.winRate(),
+		AverageKDA: t.kda(),
+	}
+}
+
+// 1試合あたりの平均
+func (t *statTotals) perGame(total int) float64 {
+	if t.games == 0 {
+		return 0
+	}
+	return float64(total) / float64(t.games)
+}
+
+// 1分あたりの平均（試合時間の合計で割る）
+func (t *statTotals) perMinute(total int) float64 {
+	if t.seconds == 0 {
+		return 0
+	}
+	return float64(total) / (float64(t.seconds) / 60)
+}
@@ -0,0 +1,248 @@
+package analysis
+
+import (
+	"cmp"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+const (
+	fixtureDir   = "../../testdata/fakeriot/matches"
+	fixturePUUID = "fake-puuid-player-0001"
+)
+
+// フィクスチャの試合を読み込む（キーはマッチIDの末尾2桁）
+func loadFixtureMatches(t *testing.T) map[string]riot.MatchDetail {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(fixtureDir, "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("フィクスチャが見つからない: %v", err)
+	}
+
+	matches := make(map[string]riot.MatchDetail, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		var match riot.MatchDetail
+		if err := json.Unmarshal(data, &match); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		id := match.Metadata.MatchID
+		matches[id[len(id)-2:]] = match
+	}
+	return matches
+}
+
+// 指定した試合だけを含む分析結果（MatchHistory は新しい順）
+func fixtureSummary(matches map[string]riot.MatchDetail, keys []string) *riot.PlayerMatchSummary {
+	summary := &riot.PlayerMatchSummary{
+		Account: riot.Account{PUUID: fixturePUUID, SummonerName: "FakePlayer", TagLine: "JP1"},
+	}
+	for _, key := range keys {
+		summary.MatchHistory = append(summary.MatchHistory, matches[key])
+	}
+	slices.SortFunc(summary.MatchHistory, func(a, b riot.MatchDetail) int {
+		return cmp.Compare(b.Info.GameStartTime, a.Info.GameStartTime)
+	})
+	summary.TotalMatches = len(summary.MatchHistory)
+	return summary
+}
+
+func fixturePlayer(t *testing.T, match *riot.MatchDetail) *riot.Participant {
+	t.Helper()
+
+	player := riot.FindParticipant(match, fixturePUUID)
+	if player == nil {
+		t.Fatalf("%s にプレイヤーがいない", match.Metadata.MatchID)
+	}
+	return player
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestKDARatio(t *testing.T) {
+	tests := []struct {
+		name                          string
+		kills, deaths, assists, games int
+		want                          float64
+	}{
+		{name: "試合なし", want: 0},
+		{name: "通常", kills: 6, deaths: 2, assists: 17, games: 1, want: 11.5},
+		{name: "デスなし1試合", kills: 2, assists: 6, games: 1, want: 8},
+		{name: "デスなしは1試合あたり", kills: 10, assists: 20, games: 4, want: 7.5},
+		{name: "キル・アシストなし", deaths: 3, games: 2, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := kdaRatio(tt.kills, tt.deaths, tt.assists, tt.games); !approxEqual(got, tt.want) {
+				t.Errorf("kdaRatio(%d, %d, %d, %d) = %v, want %v", tt.kills, tt.deaths, tt.assists, tt.games, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculatePlayerStats(t *testing.T) {
+	type champion struct {
+		name     string
+		games    int
+		wins     int
+		kdaRatio float64
+	}
+
+	tests := []struct {
+		name    string
+		matches []string
+
+		wantMatches   int
+		wantWinRate   float64
+		wantKDA       KDAStats
+		wantChampions []champion // 試合数の多い順、同数はチャンピオン名順
+	}{
+		{
+			name:          "試合なし",
+			wantChampions: []champion{},
+		},
+		{
+			// 以前の計算では KDA 比率が 0 になっていた
+			name:          "デスなしの試合",
+			matches:       []string{"10"},
+			wantMatches:   1,
+			wantWinRate:   100,
+			wantKDA:       KDAStats{Kills: 2, Deaths: 0, Assists: 6, Ratio: 8},
+			wantChampions: []champion{{"Ezreal", 1, 1, 8}},
+		},
+		{
+			name:          "デスなしの試合を含む",
+			matches:       []string{"00", "10"},
+			wantMatches:   2,
+			wantWinRate:   100,
+			wantKDA:       KDAStats{Kills: 4, Deaths: 1, Assists: 11.5, Ratio: 15.5},
+			wantChampions: []champion{{"Ezreal", 2, 2, 15.5}},
+		},
+		{
+			name:        "ソロランク",
+			matches:     []string{"00", "01", "03", "05", "07", "08", "10", "11"},
+			wantMatches: 8,
+			wantWinRate: 75,
+			wantKDA:     KDAStats{Kills: 48.0 / 8, Deaths: 33.0 / 8, Assists: 63.0 / 8, Ratio: 111.0 / 33},
+			wantChampions: []champion{
+				{"Kaisa", 3, 1, 53.0 / 11},
+				{"Ezreal", 2, 2, 31.0 / 2},
+				{"Jinx", 2, 2, 21.0 / 16},
+				{"Caitlyn", 1, 1, 6.0 / 4},
+			},
+		},
+		{
+			name:        "全試合",
+			matches:     []string{"00", "01", "02", "03", "04", "05", "06", "07", "08", "09", "10", "11"},
+			wantMatches: 12,
+			wantWinRate: 800.0 / 12,
+			wantKDA:     KDAStats{Kills: 66.0 / 12, Deaths: 42.0 / 12, Assists: 91.0 / 12, Ratio: 157.0 / 42},
+			wantChampions: []champion{
+				{"Kaisa", 4, 1, 62.0 / 13},
+				{"Caitlyn", 3, 2, 31.0 / 10},
+				{"Ezreal", 3, 3, 43.0 / 3},
+				{"Jinx", 2, 2, 21.0 / 16},
+			},
+		},
+	}
+
+	fixtures := loadFixtureMatches(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := CalculatePlayerStats(fixtureSummary(fixtures, tt.matches))
+
+			if stats.TotalMatches != tt.wantMatches {
+				t.Errorf("TotalMatches = %d, want %d", stats.TotalMatches, tt.wantMatches)
+			}
+			if !approxEqual(stats.WinRate, tt.wantWinRate) {
+				t.Errorf("WinRate = %v, want %v", stats.WinRate, tt.wantWinRate)
+			}
+
+			kda := stats.AverageKDA
+			if !approxEqual(kda.Kills, tt.wantKDA.Kills) || !approxEqual(kda.Deaths, tt.wantKDA.Deaths) ||
+				!approxEqual(kda.Assists, tt.wantKDA.Assists) || !approxEqual(kda.Ratio, tt.wantKDA.Ratio) {
+				t.Errorf("AverageKDA = %+v, want %+v", kda, tt.wantKDA)
+			}
+
+			if len(stats.MostPlayedChampions) != len(tt.wantChampions) {
+				t.Fatalf("MostPlayedChampions = %+v, want %+v", stats.MostPlayedChampions, tt.wantChampions)
+			}
+			for i, want := range tt.wantChampions {
+				got := stats.MostPlayedChampions[i]
+				if got.ChampionName != want.name || got.GamesPlayed != want.games || got.Wins != want.wins {
+					t.Errorf("MostPlayedChampions[%d] = %s %d試合 %d勝, want %s %d試合 %d勝",
+						i, got.ChampionName, got.GamesPlayed, got.Wins, want.name, want.games, want.wins)
+				}
+				if !approxEqual(got.AverageKDA.Ratio, want.kdaRatio) {
+					t.Errorf("%s の AverageKDA.Ratio = %v, want %v", want.name, got.AverageKDA.Ratio, want.kdaRatio)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculatePlayerStatsRecentForm(t *testing.T) {
+	tests := []struct {
+		name       string
+		matches    []string
+		wantLast5  FormStats
+		wantLast10 FormStats
+	}{
+		{
+			name:    "5試合未満",
+			matches: []string{"10", "11"},
+			wantLast5: FormStats{WinRate: 50,
+				AverageKDA: KDAStats{Kills: 6.5, Deaths: 1.5, Assists: 3.5, Ratio: 20.0 / 3}},
+			wantLast10: FormStats{WinRate: 50,
+				AverageKDA: KDAStats{Kills: 6.5, Deaths: 1.5, Assists: 3.5, Ratio: 20.0 / 3}},
+		},
+		{
+			// 新しい順に 11, 10, 09, 08, 07 が直近5試合
+			name:    "全試合",
+			matches: []string{"00", "01", "02", "03", "04", "05", "06", "07", "08", "09", "10", "11"},
+			wantLast5: FormStats{WinRate: 60,
+				AverageKDA: KDAStats{Kills: 34.0 / 5, Deaths: 18.0 / 5, Assists: 36.0 / 5, Ratio: 70.0 / 18}},
+			wantLast10: FormStats{WinRate: 60,
+				AverageKDA: KDAStats{Kills: 53.0 / 10, Deaths: 31.0 / 10, Assists: 69.0 / 10, Ratio: 122.0 / 31}},
+		},
+	}
+
+	fixtures := loadFixtureMatches(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := CalculatePlayerStats(fixtureSummary(fixtures, tt.matches)).RecentForm
+
+			for _, c := range []struct {
+				label     string
+				got, want FormStats
+			}{
+				{"Last5Games", form.Last5Games, tt.wantLast5},
+				{"Last10Games", form.Last10Games, tt.wantLast10},
+			} {
+				got, want := c.got, c.want
+				if !approxEqual(got.WinRate, want.WinRate) ||
+					!approxEqual(got.AverageKDA.Kills, want.AverageKDA.Kills) ||
+					!approxEqual(got.AverageKDA.Deaths, want.AverageKDA.Deaths) ||
+					!approxEqual(got.AverageKDA.Assists, want.AverageKDA.Assists) ||
+					!approxEqual(got.AverageKDA.Ratio, want.AverageKDA.Ratio) {
+					t.Errorf("%s = %+v, want %+v", c.label, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package analysis
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+// 一緒にプレイしたチームメイトとして集計する最小の試合数
+const synergyMinGames = 2
+
+// 自分と味方のチャンピオンの組み合わせごとの成績
+type ChampionPairStats struct {
+	ChampionName     string  `json:"championName"`
+	TeammateChampion string  `json:"teammateChampion"`
+	Games            int     `json:"games"`
+	Wins             int     `json:"wins"`
+	WinRate          float64 `json:"winRate"`
+}
+
+// 同じチームで複数回プレイしたチームメイトとの成績
+type TeammateStats struct {
+	PUUID         string              `json:"puuid"`
+	GameName      string              `json:"gameName"` // 最新の試合の Riot ID
+	TagLine       string              `json:"tagLine"`
+	Games         int                 `json:"games"` // 一緒にプレイした試合数
+	Wins          int                 `json:"wins"`
+	WinRate       float64             `json:"winRate"`
+	GamesApart    int                 `json:"gamesApart"`    // 一緒にプレイしていない試合数
+	WinRateApart  float64             `json:"winRateApart"`  // 一緒にプレイしていない試合の勝率
+	ChampionPairs []ChampionPairStats `json:"championPairs"` // 試合数の多い順
+}
+
+// チームメイトとの相性のレポート
+type SynergyReport struct {
+	Teammates []TeammateStats `json:"teammates"` // 一緒にプレイした試合数の多い順
+}
+
+// 分析対象の試合から、2試合以上同じチームだったチームメイトとの成績を集計
+func CalculateSynergy(summary *riot.PlayerMatchSummary) *SynergyReport {
+	report := &SynergyReport{Teammates: []TeammateStats{}}
+
+	var totalGames, totalWins int
+	teammates := make(map[string]*teammateTotals)
+
+	// MatchHistory は新しい順のため、最初に見つかった Riot ID が最新
+	for i := range summary.MatchHistory {
+		match := &summary.MatchHistory[i]
+
+		player := riot.FindParticipant(match, summary.Account.PUUID)
+		if player == nil {
+			continue
+		}
+
+		totalGames++
+		if player.Win {
+			totalWins++
+		}
+
+		for j := range match.Info.Participants {
+			teammate := &match.Info.Participants[j]
+			if teammate.TeamID != player.TeamID || teammate.PUUID == player.PUUID || !isPlayerPUUID(teammate.PUUID) {
+				continue
+			}
+
+			if teammates[teammate.PUUID] == nil {
+				teammates[teammate.PUUID] = &teammateTotals{pairs: make(map[championPair]*pairTotals)}
+			}
+			teammates[teammate.PUUID].add(player, teammate)
+		}
+	}
+
+	for puuid, t := range teammates {
+		if t.games < synergyMinGames {
+			continue
+		}
+		report.Teammates = append(report.Teammates, t.stats(puuid, totalGames, totalWins))
+	}
+
+	slices.SortFunc(report.Teammates, func(a, b TeammateStats) int {
+		if c := cmp.Compare(b.Games, a.Games); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.WinRate, a.WinRate); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.PUUID, b.PUUID)
+	})
+
+	return report
+}
+
+// ボットや PUUID のない参加者は集計しない
+func isPlayerPUUID(puuid string) bool {
+	return puuid != "" && puuid != "BOT"
+}
+
+type championPair struct{ champion, teammate string }
+
+type pairTotals struct {
+	games, wins int
+}
+
+type teammateTotals struct {
+	gameName, tagLine string
+	games, wins       int
+	pairs             map[championPair]*pairTotals
+}
+
+func (t *teammateTotals) add(player, teammate *riot.Participant) {
+	// 新しい試合の Riot ID を優先する（古い試合では空の場合がある）
+	if t.gameName == "" {
+		t.gameName, t.tagLine = teammate.RiotIDGameName, teammate.RiotIDTagline
+	}
+
+	t.games++
+	if player.Win {
+		t.wins++
+	}
+
+	key := championPair{player.ChampionName, teammate.ChampionName}
+	if t.pairs[key] == nil {
+		t.pairs[key] = &pairTotals{}
+	}
+	t.pairs[key].games++
+	if player.Win {
+		t.pairs[key].wins++
+	}
+}
+
+// totalGames・totalWins はプレイヤーが見つかった全試合の合計
+func (t *teammateTotals) stats(puuid string, totalGames, totalWins int) TeammateStats {
+	stats := TeammateStats{
+		PUUID:         puuid,
+		GameName:      t.gameName,
+		TagLine:       t.tagLine,
+		Games:         t.games,
+		Wins:          t.wins,
+		WinRate:       percentage(t.wins, t.games),
+		GamesApart:    totalGames - t.games,
+		WinRateApart:  percentage(totalWins-t.wins, totalGames-t.games),
+		ChampionPairs: make([]ChampionPairStats, 0, len(t.pairs)),
+	}
+
+	for key, pair := range t.pairs {
+		stats.ChampionPairs = append(stats.ChampionPairs, ChampionPairStats{
+			ChampionName:     key.champion,
+			TeammateChampion: key.teammate,
+			Games:            pair.games,
+			Wins:             pair.wins,
+			WinRate:          percentage(pair.wins, pair.games),
+		})
+	}
+
+	slices.SortFunc(stats.ChampionPairs, func(a, b ChampionPairStats) int {
+		if c := cmp.Compare(b.Games, a.Games); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.ChampionName, b.ChampionName); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.TeammateChampion, b.TeammateChampion)
+	})
+
+	return stats
+}
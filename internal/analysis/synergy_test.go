@@ -0,0 +1,159 @@
+package analysis
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+const (
+	duoPUUID    = "duo"
+	friendPUUID = "friend"
+)
+
+// プレイヤーと味方・敵の PUUID を指定した試合（味方のチャンピオンは PUUID ごとに固定）
+type synergyGame struct {
+	champion string
+	win      bool
+	allies   []string
+	enemies  []string
+}
+
+func synergyMatch(g synergyGame) riot.MatchDetail {
+	var match riot.MatchDetail
+	match.Info.Participants = []riot.Participant{
+		{PUUID: fixturePUUID, TeamID: 100, ChampionName: g.champion, Win: g.win},
+	}
+	for _, puuid := range g.allies {
+		match.Info.Participants = append(match.Info.Participants, riot.Participant{
+			PUUID: puuid, TeamID: 100, ChampionName: "Champion-" + puuid, Win: g.win,
+			RiotIDGameName: puuid, RiotIDTagline: "JP1",
+		})
+	}
+	for _, puuid := range g.enemies {
+		match.Info.Participants = append(match.Info.Participants, riot.Participant{
+			PUUID: puuid, TeamID: 200, ChampionName: "Champion-" + puuid, Win: !g.win,
+		})
+	}
+	return match
+}
+
+func TestCalculateSynergy(t *testing.T) {
+	type teammate struct {
+		puuid        string
+		games, wins  int
+		winRate      float64
+		gamesApart   int
+		winRateApart float64
+	}
+
+	tests := []struct {
+		name  string
+		games []synergyGame
+		want  []teammate // 一緒にプレイした試合数の多い順
+	}{
+		{
+			name:  "試合なし",
+			games: nil,
+			want:  nil,
+		},
+		{
+			name: "一緒にプレイした試合とそれ以外",
+			games: []synergyGame{
+				{champion: "Kaisa", win: true, allies: []string{duoPUUID}},
+				{champion: "Kaisa", win: true, allies: []string{duoPUUID}},
+				{champion: "Kaisa", win: false, allies: []string{duoPUUID}},
+				{champion: "Kaisa", win: false},
+				{champion: "Kaisa", win: true},
+			},
+			want: []teammate{{duoPUUID, 3, 2, 66.66666666666667, 2, 50}},
+		},
+		{
+			// 敵チームだった試合は一緒にプレイしていない試合として数える
+			name: "敵チームにいた試合",
+			games: []synergyGame{
+				{champion: "Kaisa", win: true, allies: []string{duoPUUID}},
+				{champion: "Kaisa", win: false, allies: []string{duoPUUID}},
+				{champion: "Kaisa", win: true, enemies: []string{duoPUUID}},
+				{champion: "Kaisa", win: true, enemies: []string{duoPUUID}},
+			},
+			want: []teammate{{duoPUUID, 2, 1, 50, 2, 100}},
+		},
+		{
+			name: "1試合だけのチームメイト・ボットは除く",
+			games: []synergyGame{
+				{champion: "Kaisa", win: true, allies: []string{duoPUUID, friendPUUID, "BOT"}},
+				{champion: "Kaisa", win: false, allies: []string{duoPUUID, "BOT", ""}},
+			},
+			want: []teammate{{duoPUUID, 2, 1, 50, 0, 0}},
+		},
+		{
+			name: "試合数の多い順",
+			games: []synergyGame{
+				{champion: "Kaisa", win: true, allies: []string{duoPUUID, friendPUUID}},
+				{champion: "Kaisa", win: true, allies: []string{friendPUUID}},
+				{champion: "Kaisa", win: false, allies: []string{duoPUUID, friendPUUID}},
+			},
+			want: []teammate{
+				{friendPUUID, 3, 2, 66.66666666666667, 0, 0},
+				{duoPUUID, 2, 1, 50, 1, 100},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := &riot.PlayerMatchSummary{Account: riot.Account{PUUID: fixturePUUID}}
+			for _, g := range tt.games {
+				summary.MatchHistory = append(summary.MatchHistory, synergyMatch(g))
+			}
+
+			report := CalculateSynergy(summary)
+
+			var got []teammate
+			for _, m := range report.Teammates {
+				got = append(got, teammate{m.PUUID, m.Games, m.Wins, m.WinRate, m.GamesApart, m.WinRateApart})
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Teammates = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				g, w := got[i], tt.want[i]
+				if g.puuid != w.puuid || g.games != w.games || g.wins != w.wins || g.gamesApart != w.gamesApart ||
+					!approxEqual(g.winRate, w.winRate) || !approxEqual(g.winRateApart, w.winRateApart) {
+					t.Errorf("Teammates[%d] = %+v, want %+v", i, g, w)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateSynergyChampionPairs(t *testing.T) {
+	summary := &riot.PlayerMatchSummary{Account: riot.Account{PUUID: fixturePUUID}}
+	for _, g := range []synergyGame{
+		{champion: "Kaisa", win: true, allies: []string{duoPUUID}},
+		{champion: "Jinx", win: false, allies: []string{duoPUUID}},
+		{champion: "Kaisa", win: false, allies: []string{duoPUUID}},
+	} {
+		summary.MatchHistory = append(summary.MatchHistory, synergyMatch(g))
+	}
+
+	report := CalculateSynergy(summary)
+	if len(report.Teammates) != 1 {
+		t.Fatalf("Teammates = %+v, want 1", report.Teammates)
+	}
+
+	duo := report.Teammates[0]
+	if duo.GameName != duoPUUID || duo.TagLine != "JP1" {
+		t.Errorf("Riot ID = %s#%s, want %s#JP1", duo.GameName, duo.TagLine, duoPUUID)
+	}
+
+	want := []ChampionPairStats{
+		{ChampionName: "Kaisa", TeammateChampion: "Champion-duo", Games: 2, Wins: 1, WinRate: 50},
+		{ChampionName: "Jinx", TeammateChampion: "Champion-duo", Games: 1, Wins: 0, WinRate: 0},
+	}
+	if !slices.Equal(duo.ChampionPairs, want) {
+		t.Errorf("ChampionPairs = %+v, want %+v", duo.ChampionPairs, want)
+	}
+}
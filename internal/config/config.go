@@ -3,13 +3,33 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	RiotAPIKey string
-	Region     string
+	RiotAPIKey  string
+	Region      string
+	Platform    string // ランク情報等の取得先（jp1 など、空の場合は Region から推定）
+	RiotBaseURL string // Riot API のベースURL（ローカルのモック等に向ける場合に指定）
+
+	// マッチキャッシュ
+	StoreDir        string // 保存先ディレクトリ（空の場合はキャッシュ無効）
+	StoreMaxMatches int    // 保存する最大試合数（0で無制限）
+
+	// マッチ詳細の並行取得数
+	FetchWorkers int
+
+	// Data Dragon の静的データ（ID から名前・アイコンへの変換）
+	StaticDataDir    string // 取り込み先ディレクトリ（空の場合は使用しない）
+	StaticDataLocale string // 使用する言語（ja_JP など）
+
+	// LP 推移を記録するために定期的にランクを取得するプレイヤー（"gameName#tagLine"、別サーバーは "gameName#tagLine@kr" のように指定）
+	TrackedPlayers     []string
+	LeaguePollInterval time.Duration
 }
 
 func Load() *Config {
@@ -18,8 +38,19 @@ func Load() *Config {
 	}
 
 	return &Config{
-		RiotAPIKey: getEnv("RIOT_API_KEY", ""),
-		Region:     getEnv("REGION", "asia"),
+		RiotAPIKey:      getEnv("RIOT_API_KEY", ""),
+		Region:          getEnv("REGION", "asia"),
+		Platform:        getEnv("PLATFORM", ""),
+		RiotBaseURL:     getEnv("RIOT_BASE_URL", ""),
+		StoreDir:        getEnvAllowEmpty("STORE_DIR", "./data"),
+		StoreMaxMatches: getEnvInt("STORE_MAX_MATCHES", 5000),
+		FetchWorkers:    getEnvInt("FETCH_WORKERS", 4),
+
+		StaticDataDir:    getEnvAllowEmpty("STATIC_DATA_DIR", "./data/staticdata"),
+		StaticDataLocale: getEnv("STATIC_DATA_LOCALE", "ja_JP"),
+
+		TrackedPlayers:     getEnvList("TRACKED_PLAYERS"),
+		LeaguePollInterval: getEnvDuration("LEAGUE_POLL_INTERVAL", 30*time.Minute),
 	}
 }
 
@@ -29,3 +60,50 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// 空文字の明示的な指定を許可する（未設定の場合のみデフォルト値）
+func getEnvAllowEmpty(key, defaultValue string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Warning: %s の値が不正です (%q): デフォルト値 %d を使用", key, value, defaultValue)
+		return defaultValue
+	}
+	return n
+}
+
+// カンマ区切りの値（空要素は除外）
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Warning: %s の値が不正です (%q): デフォルト値 %v を使用", key, value, defaultValue)
+		return defaultValue
+	}
+	return d
+}
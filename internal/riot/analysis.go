@@ -0,0 +1,170 @@
+package riot
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// プレイヤー分析の条件
+type AnalysisOptions struct {
+	MatchType  string      // 結果に付けるラベル（ranked / normal / aram / all など）
+	MatchCount int         // 取得する最大試合数
+	Filter     MatchFilter // 分析対象の試合の条件
+
+	// 分析対象の試合のタイムラインも取得する（レーン戦分析用、1試合につき1リクエスト追加）
+	IncludeTimelines bool
+}
+
+// ゲーム種別から分析条件を作成（未対応の種別の場合は ok=false）
+func AnalysisOptionsForGameType(gameType string, matchCount int) (AnalysisOptions, bool) {
+	filter, ok := GameTypeFilters[gameType]
+	if !ok {
+		return AnalysisOptions{}, false
+	}
+
+	return AnalysisOptions{
+		MatchType:  gameType,
+		MatchCount: matchCount,
+		Filter:     filter,
+	}, true
+}
+
+// プレイヤーの分析データを取得（キャンセル対応）
+func (c *Client) GetPlayerAnalysis(ctx context.Context, account *Account, opts AnalysisOptions) (*PlayerMatchSummary, error) {
+	startTime := time.Now()
+	c.report(ctx, ProgressEvent{Type: ProgressPhaseStart, Phase: PhaseMatchIDs, Total: opts.MatchCount,
+		Message: fmt.Sprintf("マッチ履歴を取得中（種別: %s, 最大%d試合）", opts.MatchType, opts.MatchCount)})
+
+	// キュー・期間は match-v5 側で絞り込む
+	historyOpts := opts.Filter.historyOptions(opts.MatchCount)
+	matchIDs, err := c.syncMatchHistories(ctx, account.PUUID, historyOpts)
+	if err != nil {
+		return nil, fmt.Errorf("マッチ履歴取得エラー: %w", err)
+	}
+
+	c.report(ctx, ProgressEvent{Type: ProgressPhaseDone, Phase: PhaseMatchIDs, Fetched: len(matchIDs), Total: len(matchIDs),
+		Elapsed: time.Since(startTime), Message: fmt.Sprintf("取得したマッチ数: %d", len(matchIDs))})
+
+	// 現在のランク（取得できなくても分析は続行）
+	startTime = time.Now()
+	c.report(ctx, ProgressEvent{Type: ProgressPhaseStart, Phase: PhaseLeague})
+
+	leagueEntries, err := c.RecordLeagueSnapshotWithContext(ctx, account.PUUID)
+	if err != nil {
+		c.report(ctx, ProgressEvent{Type: ProgressWarning, Phase: PhaseLeague, Message: "ランク情報の取得に失敗", Err: err})
+	}
+
+	leagueHistory, err := c.GetLeagueHistory(account.PUUID)
+	if err != nil {
+		c.report(ctx, ProgressEvent{Type: ProgressWarning, Phase: PhaseLeague, Message: "ランク履歴の読み込みに失敗", Err: err})
+	}
+
+	c.report(ctx, ProgressEvent{Type: ProgressPhaseDone, Phase: PhaseLeague, Elapsed: time.Since(startTime)})
+
+	startTime = time.Now()
+	c.report(ctx, ProgressEvent{Type: ProgressPhaseStart, Phase: PhaseMastery})
+
+	masteries, err := c.GetChampionMasteriesWithContext(ctx, account.PUUID)
+	if err != nil {
+		c.report(ctx, ProgressEvent{Type: ProgressWarning, Phase: PhaseMastery, Message: "チャンピオンマスタリーの取得に失敗", Err: err})
+	}
+
+	c.report(ctx, ProgressEvent{Type: ProgressPhaseDone, Phase: PhaseMastery, Elapsed: time.Since(startTime)})
+
+	if len(matchIDs) == 0 {
+		return &PlayerMatchSummary{
+			Account:       *account,
+			MatchHistory:  []MatchDetail{},
+			GeneratedAt:   time.Now(),
+			TotalMatches:  0,
+			MatchType:     opts.MatchType,
+			LeagueEntries: leagueEntries,
+			LeagueHistory: leagueHistory,
+
+			ChampionMasteries: masteries,
+		}, nil
+	}
+
+	// 各マッチの詳細を並行取得
+	results, err := c.FetchMatchDetailsWithContext(ctx, matchIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 次回の差分同期の境界（条件ごとの最新の試合、matchIDs は新しい順）
+	for _, query := range historyOpts {
+		c.recordLatestGameStart(ctx, account.PUUID, query, latestMatch(results, query.Queue))
+	}
+
+	matchDetails := []MatchDetail{}
+	for _, result := range results {
+		// 取得に失敗した試合は fetchConcurrently が skipped として通知済み
+		if result.Err != nil {
+			continue
+		}
+
+		// チャンピオン・ポジションなど API で絞り込めない条件はここで判定
+		if opts.Filter.Match(result.Detail, account.PUUID) {
+			matchDetails = append(matchDetails, *result.Detail)
+		}
+	}
+
+	summary := &PlayerMatchSummary{
+		Account:       *account,
+		MatchHistory:  matchDetails,
+		GeneratedAt:   time.Now(),
+		TotalMatches:  len(matchDetails),
+		MatchType:     opts.MatchType,
+		LeagueEntries: leagueEntries,
+		LeagueHistory: leagueHistory,
+
+		ChampionMasteries: masteries,
+	}
+
+	if opts.IncludeTimelines && len(matchDetails) > 0 {
+		timelines, err := c.fetchTimelines(ctx, matchDetails)
+		if err != nil {
+			return nil, err
+		}
+		summary.Timelines = timelines
+	}
+
+	return summary, nil
+}
+
+// 取得結果のうち queueID の最新の試合（queueID が0の場合は先頭の試合）
+func latestMatch(results []MatchFetchResult, queueID int) *MatchDetail {
+	if queueID == 0 {
+		return results[0].Detail
+	}
+	for _, result := range results {
+		if result.Detail != nil && result.Detail.Info.QueueID == queueID {
+			return result.Detail
+		}
+	}
+	return nil
+}
+
+// 分析対象の試合のタイムラインを取得（取得に失敗した試合は含めない）
+func (c *Client) fetchTimelines(ctx context.Context, matches []MatchDetail) (map[string]*MatchTimeline, error) {
+	matchIDs := make([]string, len(matches))
+	for i, match := range matches {
+		matchIDs[i] = match.Metadata.MatchID
+	}
+
+	results, err := c.FetchMatchTimelinesWithContext(ctx, matchIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	timelines := make(map[string]*MatchTimeline, len(results))
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		timelines[result.MatchID] = result.Timeline
+	}
+
+	return timelines, nil
+}
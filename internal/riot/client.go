@@ -1,40 +1,77 @@
 package riot
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type Client struct {
 	APIKey      string
-	Region      string
+	Region      string // リージョン（asia / americas / europe / sea）またはプラットフォーム（jp1 など）
+	Platform    string // プラットフォーム（jp1 / kr / na1 など、指定した場合は Region より優先）
+	BaseURL     string // {region} をルーティング値に置換するテンプレート
+	UserAgent   string
 	HTTPClient  *http.Client
 	RateLimiter *RateLimiter
+	Retry       RetryPolicy
+	MatchStore  MatchStore // nil の場合はキャッシュしない
+
+	// マッチ詳細の並行取得数（0以下で DefaultFetchWorkers）
+	FetchWorkers int
+
+	// nil の場合は毎回全件取得
+	HistoryStore PlayerHistoryStore
+
+	// nil の場合はタイムラインをキャッシュしない
+	TimelineStore TimelineStore
+
+	// nil の場合はランクの推移を記録しない
+	LeagueHistoryStore LeagueHistoryStore
+
+	// 進捗・警告の通知先（nil の場合は通知しない、ContextWithProgress で呼び出しごとに上書き可能）
+	Progress ProgressReporter
 }
 
-func NewClient(apiKey, region string) *Client {
-	return &Client{
-		APIKey: apiKey,
-		Region: region,
+func NewClient(apiKey, region string, opts ...Option) *Client {
+	c := &Client{
+		APIKey:  apiKey,
+		Region:  region,
+		BaseURL: DefaultBaseURL,
 		HTTPClient: &http.Client{
 			Timeout: time.Second * 30, // タイムアウトを長めに設定
 		},
 		RateLimiter: NewRateLimiter(),
+		Retry:       DefaultRetryPolicy(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
+
+	return c
 }
 
 // レート制限対応のHTTPリクエスト実行（改善版）
-func (c *Client) doRequestWithRateLimit(ctx context.Context, req *http.Request) (*http.Response, error) {
-	const maxRetries = 3
+// レート制限はルーティング値（asia / jp1 など）ごとに独立して管理する
+// ベースURLのテンプレートによっては複数のルーティング値が同じホストになるため、ホストではなく呼び出し元のルーティング値を使う
+func (c *Client) doRequestWithRateLimit(ctx context.Context, req *http.Request, routing, method string) (*http.Response, error) {
+	maxRetries := max(c.Retry.MaxAttempts, 1)
+
+	// レート制限の待機も呼び出し元に通知する
+	ctx = c.progressContext(ctx)
 
 	for attempt := 0; attempt < maxRetries; attempt++ {
 		// レート制限チェック
-		if err := c.RateLimiter.Wait(ctx); err != nil {
+		if err := c.RateLimiter.Wait(ctx, routing, method); err != nil {
 			return nil, fmt.Errorf("レート制限待機エラー: %w", err)
 		}
 
@@ -43,10 +80,10 @@ func (c *Client) doRequestWithRateLimit(ctx context.Context, req *http.Request)
 		if err != nil {
 			// ネットワークエラーの場合は短時間待機後にリトライ
 			if attempt < maxRetries-1 {
-				fmt.Printf("ネットワークエラー (試行 %d/%d): %v - 1秒後にリトライ\n",
-					attempt+1, maxRetries, err)
+				c.report(ctx, ProgressEvent{Type: ProgressRetry, Wait: c.Retry.NetworkErrorDelay, Routing: routing, Method: method,
+					Attempt: attempt + 1, Message: fmt.Sprintf("ネットワークエラー (試行 %d/%d)", attempt+1, maxRetries), Err: err})
 				select {
-				case <-time.After(time.Second):
+				case <-time.After(c.Retry.NetworkErrorDelay):
 					continue
 				case <-ctx.Done():
 					return nil, ctx.Err()
@@ -55,6 +92,9 @@ func (c *Client) doRequestWithRateLimit(ctx context.Context, req *http.Request)
 			return nil, fmt.Errorf("HTTPリクエストエラー: %w", err)
 		}
 
+		// 実際の制限値と使用数をレート制限に反映
+		c.RateLimiter.Update(routing, method, resp.Header)
+
 		// 成功またはクライアントエラー（4xx）の場合はそのまま返す
 		if resp.StatusCode < 500 && resp.StatusCode != 429 {
 			return resp, nil
@@ -63,7 +103,7 @@ func (c *Client) doRequestWithRateLimit(ctx context.Context, req *http.Request)
 		// 429 Too Many Requests の場合
 		if resp.StatusCode == 429 {
 			retryAfter := resp.Header.Get("Retry-After")
-			var waitDuration time.Duration = 2 * time.Second // デフォルト待機時間
+			waitDuration := c.Retry.RateLimitDelay // デフォルト待機時間
 
 			if retryAfter != "" {
 				if seconds, err := time.ParseDuration(retryAfter + "s"); err == nil {
@@ -71,12 +111,15 @@ func (c *Client) doRequestWithRateLimit(ctx context.Context, req *http.Request)
 				}
 			}
 
-			fmt.Printf("429エラー: %v後に再試行 (試行 %d/%d)\n",
-				waitDuration, attempt+1, maxRetries)
+			// 超過した制限（application / method）を他のリクエストも含めて停止
+			c.RateLimiter.Block(routing, method, resp.Header, waitDuration)
 
 			resp.Body.Close() // レスポンスボディを閉じる
 
 			if attempt < maxRetries-1 {
+				c.report(ctx, ProgressEvent{Type: ProgressRetry, Wait: waitDuration, Routing: routing, Method: method,
+					Attempt: attempt + 1, Message: fmt.Sprintf("429エラー (試行 %d/%d)", attempt+1, maxRetries)})
+
 				select {
 				case <-time.After(waitDuration):
 					continue
@@ -85,17 +128,23 @@ func (c *Client) doRequestWithRateLimit(ctx context.Context, req *http.Request)
 				}
 			}
 
-			return nil, fmt.Errorf("レート制限エラー: 最大試行回数に到達")
+			return nil, fmt.Errorf("レート制限エラー: %w", &RiotAPIError{
+				StatusCode: resp.StatusCode,
+				Endpoint:   req.URL.Path,
+				Message:    "最大試行回数に到達",
+				RetryAfter: waitDuration,
+			})
 		}
 
 		// 5xxサーバーエラーの場合
 		if resp.StatusCode >= 500 {
+			body, _ := io.ReadAll(resp.Body)
 			resp.Body.Close()
 
 			if attempt < maxRetries-1 {
-				waitTime := time.Duration(attempt+1) * time.Second
-				fmt.Printf("サーバーエラー %d: %v後に再試行 (試行 %d/%d)\n",
-					resp.StatusCode, waitTime, attempt+1, maxRetries)
+				waitTime := time.Duration(attempt+1) * c.Retry.ServerErrorDelay
+				c.report(ctx, ProgressEvent{Type: ProgressRetry, Wait: waitTime, Routing: routing, Method: method,
+					Attempt: attempt + 1, Message: fmt.Sprintf("サーバーエラー %d (試行 %d/%d)", resp.StatusCode, attempt+1, maxRetries)})
 
 				select {
 				case <-time.After(waitTime):
@@ -105,7 +154,11 @@ func (c *Client) doRequestWithRateLimit(ctx context.Context, req *http.Request)
 				}
 			}
 
-			return nil, fmt.Errorf("サーバーエラー: ステータスコード %d", resp.StatusCode)
+			return nil, fmt.Errorf("サーバーエラー: %w", &RiotAPIError{
+				StatusCode: resp.StatusCode,
+				Endpoint:   req.URL.Path,
+				Message:    riotErrorMessage(body),
+			})
 		}
 
 		// その他のエラー
@@ -115,420 +168,417 @@ func (c *Client) doRequestWithRateLimit(ctx context.Context, req *http.Request)
 	return nil, fmt.Errorf("最大試行回数に到達")
 }
 
-// アカウント情報取得（レート制限対応）
-func (c *Client) GetAccountByRiotID(gameName, tagLine string) (*Account, error) {
-	ctx := context.Background()
-
-	baseURL := fmt.Sprintf("https://%s.api.riotgames.com", c.Region)
-	endpoint := fmt.Sprintf("/riot/account/v1/accounts/by-riot-id/%s/%s",
-		url.PathEscape(gameName),
-		url.PathEscape(tagLine))
-
-	fullURL := baseURL + endpoint
-
-	req, err := http.NewRequest("GET", fullURL, nil)
+// ルーティング値のベースURLに GET リクエストを実行し、JSONレスポンスを out にデコード
+func (c *Client) getJSON(ctx context.Context, routing, method, endpoint string, out any) error {
+	req, err := http.NewRequest("GET", c.baseURL(routing)+endpoint, nil)
 	if err != nil {
-		return nil, fmt.Errorf("リクエスト作成エラー: %w", err)
+		return fmt.Errorf("リクエスト作成エラー: %w", err)
 	}
 
 	req.Header.Add("X-Riot-Token", c.APIKey)
 	req.Header.Add("Accept", "application/json")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
 
-	resp, err := c.doRequestWithRateLimit(ctx, req)
+	resp, err := c.doRequestWithRateLimit(ctx, req, routing, method)
 	if err != nil {
-		return nil, fmt.Errorf("APIリクエストエラー: %w", err)
+		return fmt.Errorf("APIリクエストエラー: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("APIエラー (status: %d): %s", resp.StatusCode, string(body))
+		return fmt.Errorf("APIエラー: %w", &RiotAPIError{
+			StatusCode: resp.StatusCode,
+			Endpoint:   req.URL.Path,
+			Message:    riotErrorMessage(body),
+		})
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("レスポンス読み取りエラー: %w", err)
+		return fmt.Errorf("レスポンス読み取りエラー: %w", err)
 	}
 
-	var account Account
-	if err := json.Unmarshal(body, &account); err != nil {
-		return nil, fmt.Errorf("JSON解析エラー: %w", err)
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("JSON解析エラー: %w", err)
 	}
 
-	return &account, nil
+	return nil
 }
 
-// マッチ詳細取得（レート制限対応）
-func (c *Client) GetMatchDetailWithContext(ctx context.Context, matchID string) (*MatchDetail, error) {
-	matchRegion := c.getMatchRegion()
-	baseURL := fmt.Sprintf("https://%s.api.riotgames.com", matchRegion)
-	endpoint := fmt.Sprintf("/lol/match/v5/matches/%s", url.PathEscape(matchID))
+// Riot API のエラーレスポンス（{"status": {"message": ..., "status_code": ...}}）からメッセージを抽出
+func riotErrorMessage(body []byte) string {
+	var errResp struct {
+		Status struct {
+			Message string `json:"message"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Status.Message != "" {
+		return errResp.Status.Message
+	}
+	return string(body)
+}
 
-	fullURL := baseURL + endpoint
+// アカウント情報取得（レート制限対応）
+func (c *Client) GetAccountByRiotID(gameName, tagLine string) (*Account, error) {
+	ctx := context.Background()
 
-	req, err := http.NewRequest("GET", fullURL, nil)
+	routing, err := c.accountRouting()
 	if err != nil {
-		return nil, fmt.Errorf("リクエスト作成エラー: %w", err)
+		return nil, err
 	}
+	endpoint := fmt.Sprintf("/riot/account/v1/accounts/by-riot-id/%s/%s",
+		url.PathEscape(gameName),
+		url.PathEscape(tagLine))
 
-	req.Header.Add("X-Riot-Token", c.APIKey)
-	req.Header.Add("Accept", "application/json")
-
-	resp, err := c.doRequestWithRateLimit(ctx, req)
-	if err != nil {
-		return nil, fmt.Errorf("APIリクエストエラー: %w", err)
+	var account Account
+	if err := c.getJSON(ctx, routing, MethodAccountByRiotID, endpoint, &account); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("APIエラー (status: %d): %s", resp.StatusCode, string(body))
+	return &account, nil
+}
+
+// マッチ詳細取得（キャッシュ・レート制限対応）
+func (c *Client) GetMatchDetailWithContext(ctx context.Context, matchID string) (*MatchDetail, error) {
+	// キャッシュを優先して参照
+	if c.MatchStore != nil {
+		cached, ok, err := c.MatchStore.GetMatch(matchID)
+		if err != nil {
+			c.report(ctx, ProgressEvent{Type: ProgressWarning, Phase: PhaseMatches, MatchID: matchID, Message: "キャッシュの読み込みに失敗", Err: err})
+		} else if ok {
+			return cached, nil
+		}
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	routing, err := c.regionalRouting()
 	if err != nil {
-		return nil, fmt.Errorf("レスポンス読み取りエラー: %w", err)
+		return nil, err
 	}
+	endpoint := fmt.Sprintf("/lol/match/v5/matches/%s", url.PathEscape(matchID))
 
 	var matchDetail MatchDetail
-	if err := json.Unmarshal(body, &matchDetail); err != nil {
-		return nil, fmt.Errorf("JSON解析エラー: %w", err)
+	if err := c.getJSON(ctx, routing, MethodMatchDetail, endpoint, &matchDetail); err != nil {
+		return nil, err
+	}
+
+	// 取得に成功した試合をキャッシュに保存
+	if c.MatchStore != nil {
+		if err := c.MatchStore.PutMatch(&matchDetail); err != nil {
+			c.report(ctx, ProgressEvent{Type: ProgressWarning, Phase: PhaseMatches, MatchID: matchID, Message: "キャッシュの保存に失敗", Err: err})
+		}
 	}
 
 	return &matchDetail, nil
 }
 
+// マッチ履歴取得の条件（match-v5 by-puuid のクエリパラメータに対応）
+type MatchHistoryOptions struct {
+	Queue     int       // キューID（0で指定なし）
+	Type      string    // ranked / normal / tourney / tutorial（空で指定なし）
+	StartTime time.Time // この時刻以降に開始した試合のみ（ゼロ値で指定なし）
+	EndTime   time.Time // この時刻以前に開始した試合のみ（ゼロ値で指定なし）
+	Start     int       // 取得開始位置
+	Count     int       // 取得件数（100件を超える場合はページングして取得）
+}
+
+// 1リクエストで取得できるマッチIDの上限
+const matchIDsPageSize = 100
+
+// 期間指定を含まない条件を識別するキー（差分同期の履歴を条件ごとに分けるため）
+func (o MatchHistoryOptions) historyKey() string {
+	var parts []string
+	if o.Queue != 0 {
+		parts = append(parts, "queue-"+strconv.Itoa(o.Queue))
+	}
+	if o.Type != "" {
+		parts = append(parts, "type-"+o.Type)
+	}
+	if len(parts) == 0 {
+		return "all"
+	}
+	return strings.Join(parts, "_")
+}
+
 // マッチ履歴取得
 func (c *Client) GetRankedMatchHistoryWithContext(ctx context.Context, puuid string, count int) (MatchHistory, error) {
-	if count <= 0 || count > 100 {
-		count = 100
+	return c.GetMatchHistoryWithContext(ctx, puuid, MatchHistoryOptions{Count: count})
+}
+
+// 条件指定でマッチ履歴取得（条件に合うIDが Count 件集まるまでページング）
+func (c *Client) GetMatchHistoryWithContext(ctx context.Context, puuid string, opts MatchHistoryOptions) (MatchHistory, error) {
+	if opts.Count <= 0 {
+		opts.Count = matchIDsPageSize
 	}
 
-	// リクエストURL作成
-	matchRegion := c.getMatchRegion()
-	baseURL := fmt.Sprintf("https://%s.api.riotgames.com", matchRegion)
-	endpoint := fmt.Sprintf("/lol/match/v5/matches/by-puuid/%s/ids?start=0&count=%d", url.PathEscape(puuid), count)
+	matchHistory := MatchHistory{}
+	start := opts.Start
 
-	fullURL := baseURL + endpoint
+	for len(matchHistory) < opts.Count {
+		pageSize := min(opts.Count-len(matchHistory), matchIDsPageSize)
 
-	request, err := http.NewRequest("GET", fullURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("リクエスト作成エラー: %w", err)
-	}
+		page, err := c.getMatchIDsPage(ctx, puuid, opts, start, pageSize)
+		if err != nil {
+			return nil, err
+		}
 
-	request.Header.Add("X-Riot-Token", c.APIKey)
-	request.Header.Add("Accept", "application/json")
+		matchHistory = append(matchHistory, page...)
+		start += len(page)
 
-	response, err := c.HTTPClient.Do(request)
-	if err != nil {
-		return nil, fmt.Errorf("APIリクエストエラー: %w", err)
+		// 要求より少なければそれ以上の履歴はない
+		if len(page) < pageSize {
+			break
+		}
 	}
-	defer response.Body.Close()
 
-	if response.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(response.Body)
-		return nil, fmt.Errorf("APIエラー (status: %d): %s", response.StatusCode, string(body))
+	return matchHistory, nil
+}
+
+// マッチIDを1ページ分取得
+func (c *Client) getMatchIDsPage(ctx context.Context, puuid string, opts MatchHistoryOptions, start, count int) (MatchHistory, error) {
+	query := url.Values{}
+	query.Set("start", strconv.Itoa(start))
+	query.Set("count", strconv.Itoa(count))
+	if opts.Queue != 0 {
+		query.Set("queue", strconv.Itoa(opts.Queue))
+	}
+	if opts.Type != "" {
+		query.Set("type", opts.Type)
+	}
+	if !opts.StartTime.IsZero() {
+		query.Set("startTime", strconv.FormatInt(opts.StartTime.Unix(), 10))
+	}
+	if !opts.EndTime.IsZero() {
+		query.Set("endTime", strconv.FormatInt(opts.EndTime.Unix(), 10))
 	}
 
-	body, err := io.ReadAll(response.Body)
+	// リクエストURL作成
+	routing, err := c.regionalRouting()
 	if err != nil {
-		return nil, fmt.Errorf("レスポンス抽出エラー: %w", err)
+		return nil, err
 	}
+	endpoint := fmt.Sprintf("/lol/match/v5/matches/by-puuid/%s/ids?%s", url.PathEscape(puuid), query.Encode())
 
 	var matchHistory MatchHistory
-	if err := json.Unmarshal(body, &matchHistory); err != nil {
-		return nil, fmt.Errorf("JSONパースエラー: %w", err)
+	if err := c.getJSON(ctx, routing, MethodMatchIDs, endpoint, &matchHistory); err != nil {
+		return nil, err
 	}
 
 	return matchHistory, nil
 }
 
-// リージョンをマッチAPIのリージョンに変換
-func (c *Client) getMatchRegion() string {
-	switch c.Region {
-	case "asia":
-		return "asia"
-	case "kr", "jp1":
-		return "asia"
-	case "na1", "br1", "la1", "la2":
-		return "americas"
-	case "euw1", "eun1", "tr1", "ru":
-		return "europe"
-	default:
-		return "asia" // デフォルト
+// 保存済みの履歴より新しい試合のみ取得し、履歴と統合したマッチID一覧（新しい順）を返す
+func (c *Client) SyncMatchHistoryWithContext(ctx context.Context, puuid string, opts MatchHistoryOptions) (MatchHistory, error) {
+	// 期間指定がある場合は保存済み履歴と範囲が一致しないため通常取得
+	if c.HistoryStore == nil || !opts.StartTime.IsZero() || !opts.EndTime.IsZero() || opts.Start != 0 {
+		return c.GetMatchHistoryWithContext(ctx, puuid, opts)
 	}
-}
 
-// プレイヤーのランク戦分析データを取得（キャンセル対応）
-func (c *Client) GetPlayerRankedAnalysisWithContext(ctx context.Context, account *Account, matchCount int) (*PlayerMatchSummary, error) {
-	fmt.Printf("ランク戦マッチ履歴を取得中（最大%d試合）...\n", matchCount)
+	if opts.Count <= 0 {
+		opts.Count = matchIDsPageSize
+	}
 
-	// ランク戦マッチ履歴取得
-	matchIDs, err := c.GetRankedMatchHistoryWithContext(ctx, account.PUUID, matchCount)
+	key := opts.historyKey()
+	history, ok, err := c.HistoryStore.GetPlayerHistory(puuid, key)
 	if err != nil {
-		return nil, fmt.Errorf("ランク戦マッチ履歴取得エラー: %w", err)
+		c.report(ctx, ProgressEvent{Type: ProgressWarning, Phase: PhaseMatchIDs, Message: "保存済み履歴の読み込みに失敗", Err: err})
+		ok = false
 	}
 
-	fmt.Printf("取得したランク戦マッチ数: %d\n", len(matchIDs))
-
-	if len(matchIDs) == 0 {
-		return &PlayerMatchSummary{
-			Account:      *account,
-			MatchHistory: []MatchDetail{},
-			GeneratedAt:  time.Now(),
-			TotalMatches: 0,
-			MatchType:    "ranked",
-		}, nil
+	var stored MatchHistory
+	var latestGameStart time.Time
+	if ok {
+		stored = history.MatchIDs
+		latestGameStart = history.LatestGameStart
 	}
 
-	// 各マッチの詳細を取得
-	var matchDetails []MatchDetail
-	startTime := time.Now()
+	var newIDs MatchHistory
+	var connected bool // 取得した範囲が保存済みの履歴と途切れずにつながっているか
 
-	fmt.Printf("マッチ詳細取得開始...\n")
+	if len(stored) >= opts.Count && !latestGameStart.IsZero() {
+		// 保存済みの最新試合の開始時刻以降を、その試合が見つかるまでページングして問い合わせる
+		// 同時刻の試合を取りこぼさないよう、境界は重複を許容して統合時に除外する
+		query := opts
+		query.StartTime = latestGameStart
 
-	for i, matchID := range matchIDs {
-		// コンテキストキャンセルチェック
-		select {
-		case <-ctx.Done():
-			return nil, fmt.Errorf("処理がキャンセルされました: %w", ctx.Err())
-		default:
-		}
-
-		// 進捗表示
-		if i%5 == 0 {
-			elapsed := time.Since(startTime)
-			if i > 0 {
-				avgTime := elapsed / time.Duration(i)
-				remaining := avgTime * time.Duration(len(matchIDs)-i)
-				fmt.Printf("進捗: %d/%d (%.1f%%) - 経過: %v, 推定残り: %v\n",
-					i, len(matchIDs), float64(i)/float64(len(matchIDs))*100,
-					elapsed.Round(time.Second), remaining.Round(time.Second))
-			}
+		newIDs, connected, err = c.getMatchIDsUntil(ctx, puuid, query, stored[0], maxStoredHistory)
+		if err != nil {
+			return nil, err
 		}
 
-		detail, err := c.GetMatchDetailWithContext(ctx, matchID)
+		c.report(ctx, ProgressEvent{Type: ProgressInfo, Phase: PhaseMatchIDs,
+			Message: fmt.Sprintf("差分同期: 新規マッチ %d件（保存済み %d件）", len(newIDs), len(stored))})
+	} else {
+		newIDs, err = c.GetMatchHistoryWithContext(ctx, puuid, opts)
 		if err != nil {
-			fmt.Printf("⚠️  マッチ %s の取得に失敗: %v\n", matchID, err)
-			continue
+			return nil, err
 		}
 
-		if IsRankedQueue(detail.Info.QueueID) {
-			matchDetails = append(matchDetails, *detail)
-		}
-	}
+		// 直近 Count 件に保存済みの最新試合が含まれないと、その間の試合が欠ける
+		connected = len(stored) == 0 || len(newIDs) < opts.Count || slices.Contains(newIDs, stored[0])
 
-	totalTime := time.Since(startTime)
-	fmt.Printf("✅ マッチ詳細取得完了: %d試合を%vで処理\n",
-		len(matchDetails), totalTime.Round(time.Second))
+		c.report(ctx, ProgressEvent{Type: ProgressInfo, Phase: PhaseMatchIDs,
+			Message: fmt.Sprintf("保存済み履歴なし: 直近%d試合を取得", opts.Count)})
+	}
 
-	return &PlayerMatchSummary{
-		Account:      *account,
-		MatchHistory: matchDetails,
-		GeneratedAt:  time.Now(),
-		TotalMatches: len(matchDetails),
-		MatchType:    "ranked",
-	}, nil
-}
+	if !connected {
+		// 途中の試合が欠けた履歴を保存しないよう、保存済みの履歴は破棄する
+		c.report(ctx, ProgressEvent{Type: ProgressInfo, Phase: PhaseMatchIDs,
+			Message: fmt.Sprintf("前回の同期から%d試合以上経過したため保存済み履歴（%d件）を破棄", len(newIDs), len(stored))})
+		stored = nil
+	}
 
-// プレイヤーのノーマル戦分析データを取得
-func (c *Client) GetPlayerNormalAnalysisWithContext(ctx context.Context, account *Account, matchCount int) (*PlayerMatchSummary, error) {
-	fmt.Printf("ノーマル戦マッチ履歴を取得中（最大%d試合）...\n", matchCount)
+	merged := mergeMatchIDs(newIDs, stored)
 
-	matchIDs, err := c.GetRankedMatchHistoryWithContext(ctx, account.PUUID, matchCount)
-	if err != nil {
-		return nil, fmt.Errorf("マッチ履歴取得エラー: %w", err)
+	if len(merged) > maxStoredHistory {
+		merged = merged[:maxStoredHistory]
 	}
 
-	fmt.Printf("取得したマッチ数: %d\n", len(matchIDs))
-
-	if len(matchIDs) == 0 {
-		return &PlayerMatchSummary{
-			Account:      *account,
-			MatchHistory: []MatchDetail{},
-			GeneratedAt:  time.Now(),
-			TotalMatches: 0,
-			MatchType:    "normal",
-		}, nil
+	// 最新の試合が変わらない場合のみ境界の開始時刻を引き継ぐ（変わった場合はマッチ詳細の取得後に記録）
+	updated := &PlayerHistory{
+		PUUID:     puuid,
+		Query:     key,
+		MatchIDs:  merged,
+		UpdatedAt: time.Now(),
+	}
+	if len(stored) > 0 && len(merged) > 0 && merged[0] == stored[0] {
+		updated.LatestGameStart = latestGameStart
 	}
 
-	var matchDetails []MatchDetail
-	startTime := time.Now()
+	if err := c.HistoryStore.PutPlayerHistory(updated); err != nil {
+		c.report(ctx, ProgressEvent{Type: ProgressWarning, Phase: PhaseMatchIDs, Message: "履歴の保存に失敗", Err: err})
+	}
 
-	fmt.Printf("マッチ詳細取得開始...\n")
+	if len(merged) > opts.Count {
+		merged = merged[:opts.Count]
+	}
 
-	for i, matchID := range matchIDs {
-		select {
-		case <-ctx.Done():
-			return nil, fmt.Errorf("処理がキャンセルされました: %w", ctx.Err())
-		default:
-		}
+	return merged, nil
+}
 
-		if i%5 == 0 {
-			elapsed := time.Since(startTime)
-			if i > 0 {
-				avgTime := elapsed / time.Duration(i)
-				remaining := avgTime * time.Duration(len(matchIDs)-i)
-				fmt.Printf("進捗: %d/%d (%.1f%%) - 経過: %v, 推定残り: %v\n",
-					i, len(matchIDs), float64(i)/float64(len(matchIDs))*100,
-					elapsed.Round(time.Second), remaining.Round(time.Second))
-			}
-		}
+// 複数の条件のマッチ履歴をそれぞれ同期し、新しい順に統合して Count 件を返す
+// マッチIDの数値部分はプラットフォーム内で試合の作成順に振られるため、試合の開始順として扱う
+func (c *Client) syncMatchHistories(ctx context.Context, puuid string, queries []MatchHistoryOptions) (MatchHistory, error) {
+	if len(queries) == 1 {
+		return c.SyncMatchHistoryWithContext(ctx, puuid, queries[0])
+	}
 
-		detail, err := c.GetMatchDetailWithContext(ctx, matchID)
+	var merged MatchHistory
+	for _, query := range queries {
+		matchIDs, err := c.SyncMatchHistoryWithContext(ctx, puuid, query)
 		if err != nil {
-			fmt.Printf("⚠️  マッチ %s の取得に失敗: %v\n", matchID, err)
-			continue
-		}
-
-		if IsNormalQueue(detail.Info.QueueID) {
-			matchDetails = append(matchDetails, *detail)
+			return nil, err
 		}
+		merged = append(merged, matchIDs...)
 	}
 
-	totalTime := time.Since(startTime)
-	fmt.Printf("✅ マッチ詳細取得完了: %d試合を%vで処理\n",
-		len(matchDetails), totalTime.Round(time.Second))
+	slices.SortFunc(merged, func(a, b string) int {
+		return compareMatchIDs(b, a)
+	})
+	merged = slices.Compact(merged)
+
+	count := queries[0].Count
+	if count <= 0 {
+		count = matchIDsPageSize
+	}
+	if len(merged) > count {
+		merged = merged[:count]
+	}
 
-	return &PlayerMatchSummary{
-		Account:      *account,
-		MatchHistory: matchDetails,
-		GeneratedAt:  time.Now(),
-		TotalMatches: len(matchDetails),
-		MatchType:    "normal",
-	}, nil
+	return merged, nil
 }
 
-// プレイヤーのARAM分析データを取得
-func (c *Client) GetPlayerARAMAnalysisWithContext(ctx context.Context, account *Account, matchCount int) (*PlayerMatchSummary, error) {
-	fmt.Printf("ARAMマッチ履歴を取得中（最大%d試合）...\n", matchCount)
+// マッチIDを作成順に比較（"JP1_123456" の数値部分、数値でない場合は文字列で比較）
+func compareMatchIDs(a, b string) int {
+	aPrefix, aNum, aOK := splitMatchID(a)
+	bPrefix, bNum, bOK := splitMatchID(b)
+	if !aOK || !bOK || aPrefix != bPrefix {
+		return strings.Compare(a, b)
+	}
+	return cmp.Compare(aNum, bNum)
+}
 
-	matchIDs, err := c.GetRankedMatchHistoryWithContext(ctx, account.PUUID, matchCount)
+func splitMatchID(matchID string) (string, int64, bool) {
+	i := strings.LastIndex(matchID, "_")
+	if i < 0 {
+		return "", 0, false
+	}
+	n, err := strconv.ParseInt(matchID[i+1:], 10, 64)
 	if err != nil {
-		return nil, fmt.Errorf("マッチ履歴取得エラー: %w", err)
+		return "", 0, false
 	}
+	return matchID[:i], n, true
+}
 
-	fmt.Printf("取得したマッチ数: %d\n", len(matchIDs))
-
-	if len(matchIDs) == 0 {
-		return &PlayerMatchSummary{
-			Account:      *account,
-			MatchHistory: []MatchDetail{},
-			GeneratedAt:  time.Now(),
-			TotalMatches: 0,
-			MatchType:    "aram",
-		}, nil
-	}
+// プレイヤーごとに保持するマッチIDの上限
+const maxStoredHistory = 1000
 
-	var matchDetails []MatchDetail
-	startTime := time.Now()
+// stop のマッチIDが見つかるまでページングして、それより新しいマッチIDを取得
+// stop が見つかった場合、または limit 件に達する前に履歴が尽きた場合は connected=true
+func (c *Client) getMatchIDsUntil(ctx context.Context, puuid string, opts MatchHistoryOptions, stop string, limit int) (MatchHistory, bool, error) {
+	matchHistory := MatchHistory{}
+	start := 0
 
-	fmt.Printf("マッチ詳細取得開始...\n")
+	for len(matchHistory) < limit {
+		pageSize := min(limit-len(matchHistory), matchIDsPageSize)
 
-	for i, matchID := range matchIDs {
-		select {
-		case <-ctx.Done():
-			return nil, fmt.Errorf("処理がキャンセルされました: %w", ctx.Err())
-		default:
+		page, err := c.getMatchIDsPage(ctx, puuid, opts, start, pageSize)
+		if err != nil {
+			return nil, false, err
 		}
 
-		if i%5 == 0 {
-			elapsed := time.Since(startTime)
-			if i > 0 {
-				avgTime := elapsed / time.Duration(i)
-				remaining := avgTime * time.Duration(len(matchIDs)-i)
-				fmt.Printf("進捗: %d/%d (%.1f%%) - 経過: %v, 推定残り: %v\n",
-					i, len(matchIDs), float64(i)/float64(len(matchIDs))*100,
-					elapsed.Round(time.Second), remaining.Round(time.Second))
-			}
+		if i := slices.Index(page, stop); i >= 0 {
+			return append(matchHistory, page[:i]...), true, nil
 		}
 
-		detail, err := c.GetMatchDetailWithContext(ctx, matchID)
-		if err != nil {
-			fmt.Printf("⚠️  マッチ %s の取得に失敗: %v\n", matchID, err)
-			continue
-		}
+		matchHistory = append(matchHistory, page...)
+		start += len(page)
 
-		if IsARAMQueue(detail.Info.QueueID) {
-			matchDetails = append(matchDetails, *detail)
+		if len(page) < pageSize {
+			return matchHistory, true, nil
 		}
 	}
 
-	totalTime := time.Since(startTime)
-	fmt.Printf("✅ マッチ詳細取得完了: %d試合を%vで処理\n",
-		len(matchDetails), totalTime.Round(time.Second))
-
-	return &PlayerMatchSummary{
-		Account:      *account,
-		MatchHistory: matchDetails,
-		GeneratedAt:  time.Now(),
-		TotalMatches: len(matchDetails),
-		MatchType:    "aram",
-	}, nil
+	return matchHistory, false, nil
 }
 
-// プレイヤーの全ゲーム分析データを取得
-func (c *Client) GetPlayerAllAnalysisWithContext(ctx context.Context, account *Account, matchCount int) (*PlayerMatchSummary, error) {
-	fmt.Printf("全ゲームマッチ履歴を取得中（最大%d試合）...\n", matchCount)
-
-	matchIDs, err := c.GetRankedMatchHistoryWithContext(ctx, account.PUUID, matchCount)
-	if err != nil {
-		return nil, fmt.Errorf("マッチ履歴取得エラー: %w", err)
+// 差分同期の境界として、保存済み履歴の最新試合の開始時刻を記録
+// マッチIDの一覧には開始時刻が含まれないため、同期後に取得したマッチ詳細から記録する
+func (c *Client) recordLatestGameStart(ctx context.Context, puuid string, opts MatchHistoryOptions, latest *MatchDetail) {
+	if c.HistoryStore == nil || latest == nil || latest.Info.GameStartTime == 0 {
+		return
 	}
 
-	fmt.Printf("取得したマッチ数: %d\n", len(matchIDs))
-
-	if len(matchIDs) == 0 {
-		return &PlayerMatchSummary{
-			Account:      *account,
-			MatchHistory: []MatchDetail{},
-			GeneratedAt:  time.Now(),
-			TotalMatches: 0,
-			MatchType:    "all",
-		}, nil
+	history, ok, err := c.HistoryStore.GetPlayerHistory(puuid, opts.historyKey())
+	if err != nil || !ok || len(history.MatchIDs) == 0 || history.MatchIDs[0] != latest.Metadata.MatchID {
+		return
+	}
+	if !history.LatestGameStart.IsZero() {
+		return
 	}
 
-	var matchDetails []MatchDetail
-	startTime := time.Now()
-
-	fmt.Printf("マッチ詳細取得開始...\n")
+	history.LatestGameStart = time.UnixMilli(latest.Info.GameStartTime)
+	if err := c.HistoryStore.PutPlayerHistory(history); err != nil {
+		c.report(ctx, ProgressEvent{Type: ProgressWarning, Phase: PhaseMatchIDs, Message: "履歴の保存に失敗", Err: err})
+	}
+}
 
-	for i, matchID := range matchIDs {
-		select {
-		case <-ctx.Done():
-			return nil, fmt.Errorf("処理がキャンセルされました: %w", ctx.Err())
-		default:
-		}
+// 新しいマッチIDを先頭に、重複を除いて統合
+func mergeMatchIDs(newIDs, stored MatchHistory) MatchHistory {
+	seen := make(map[string]bool, len(newIDs)+len(stored))
+	merged := make(MatchHistory, 0, len(newIDs)+len(stored))
 
-		if i%5 == 0 {
-			elapsed := time.Since(startTime)
-			if i > 0 {
-				avgTime := elapsed / time.Duration(i)
-				remaining := avgTime * time.Duration(len(matchIDs)-i)
-				fmt.Printf("進捗: %d/%d (%.1f%%) - 経過: %v, 推定残り: %v\n",
-					i, len(matchIDs), float64(i)/float64(len(matchIDs))*100,
-					elapsed.Round(time.Second), remaining.Round(time.Second))
+	for _, ids := range []MatchHistory{newIDs, stored} {
+		for _, id := range ids {
+			if seen[id] {
+				continue
 			}
+			seen[id] = true
+			merged = append(merged, id)
 		}
-
-		detail, err := c.GetMatchDetailWithContext(ctx, matchID)
-		if err != nil {
-			fmt.Printf("⚠️  マッチ %s の取得に失敗: %v\n", matchID, err)
-			continue
-		}
-
-		matchDetails = append(matchDetails, *detail)
 	}
 
-	totalTime := time.Since(startTime)
-	fmt.Printf("✅ マッチ詳細取得完了: %d試合を%vで処理\n",
-		len(matchDetails), totalTime.Round(time.Second))
-
-	return &PlayerMatchSummary{
-		Account:      *account,
-		MatchHistory: matchDetails,
-		GeneratedAt:  time.Now(),
-		TotalMatches: len(matchDetails),
-		MatchType:    "all",
-	}, nil
+	return merged
 }
@@ -0,0 +1,102 @@
+package riot
+
+import (
+	"context"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// リクエストを記録して null を返すトランスポート（どの型にもデコードできる）
+type recordingTransport struct {
+	mu   sync.Mutex
+	urls []string
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	rt.urls = append(rt.urls, req.URL.Host+req.URL.Path)
+	rt.mu.Unlock()
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("null")),
+		Request:    req,
+	}, nil
+}
+
+func TestRateLimitBucketsPerRouting(t *testing.T) {
+	tests := []struct {
+		name      string
+		baseURL   string
+		platforms []string // この順にマッチ詳細・リーグエントリーを取得
+		wantURLs  []string
+		want      []string // GetStatus に現れるルーティング値
+	}{
+		{
+			name:      "既定のベースURL",
+			baseURL:   DefaultBaseURL,
+			platforms: []string{PlatformJP1, PlatformNA1},
+			wantURLs: []string{
+				"asia.api.riotgames.com/lol/match/v5/matches/M",
+				"jp1.api.riotgames.com/lol/league/v4/entries/by-puuid/P",
+				"americas.api.riotgames.com/lol/match/v5/matches/M",
+				"na1.api.riotgames.com/lol/league/v4/entries/by-puuid/P",
+			},
+			want: []string{RegionAmericas, RegionAsia, PlatformJP1, PlatformNA1},
+		},
+		{
+			// すべて同じホストになるが、制限はルーティング値ごとに分ける
+			name:      "パスにルーティング値を含むゲートウェイ",
+			baseURL:   "https://gateway.example.com/riot/{region}",
+			platforms: []string{PlatformJP1, PlatformNA1, PlatformKR},
+			wantURLs: []string{
+				"gateway.example.com/riot/asia/lol/match/v5/matches/M",
+				"gateway.example.com/riot/jp1/lol/league/v4/entries/by-puuid/P",
+				"gateway.example.com/riot/americas/lol/match/v5/matches/M",
+				"gateway.example.com/riot/na1/lol/league/v4/entries/by-puuid/P",
+				"gateway.example.com/riot/asia/lol/match/v5/matches/M",
+				"gateway.example.com/riot/kr/lol/league/v4/entries/by-puuid/P",
+			},
+			want: []string{RegionAmericas, RegionAsia, PlatformJP1, PlatformKR, PlatformNA1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &recordingTransport{}
+			client := NewClient("test-key", RegionAsia, WithBaseURL(tt.baseURL), WithTransport(transport))
+
+			ctx := context.Background()
+			for _, platform := range tt.platforms {
+				routed, err := client.ForRouting("", platform)
+				if err != nil {
+					t.Fatalf("ForRouting(%s): %v", platform, err)
+				}
+				if _, err := routed.GetMatchDetailWithContext(ctx, "M"); err != nil {
+					t.Fatalf("GetMatchDetailWithContext: %v", err)
+				}
+				if _, err := routed.GetLeagueEntriesByPUUIDWithContext(ctx, "P"); err != nil {
+					t.Fatalf("GetLeagueEntriesByPUUIDWithContext: %v", err)
+				}
+			}
+
+			if !slices.Equal(transport.urls, tt.wantURLs) {
+				t.Errorf("URL = %v, want %v", transport.urls, tt.wantURLs)
+			}
+
+			routings := make(map[string]bool)
+			for _, status := range client.RateLimiter.GetStatus() {
+				routings[status.Routing] = true
+			}
+			if got := slices.Sorted(maps.Keys(routings)); !slices.Equal(got, tt.want) {
+				t.Errorf("ルーティング値 = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -11,18 +11,20 @@ const (
 	QueueNormalBlind = 430 // ノーマル（ブラインド）
 
 	// その他
-	QueueARAM = 450 // ARAM
-	QueueURF  = 900 // URF（期間限定）
+	QueueARAM  = 450  // ARAM
+	QueueURF   = 900  // URF（期間限定）
+	QueueArena = 1700 // アリーナ
 )
 
 // キューID から キュー名への変換
 var QueueIDToName = map[int]string{
-	420: "ソロ/デュオランク",
-	440: "フレックスランク",
-	400: "ノーマル（ドラフト）",
-	430: "ノーマル（ブラインド）",
-	450: "ARAM",
-	900: "URF",
+	420:  "ソロ/デュオランク",
+	440:  "フレックスランク",
+	400:  "ノーマル（ドラフト）",
+	430:  "ノーマル（ブラインド）",
+	450:  "ARAM",
+	900:  "URF",
+	1700: "アリーナ",
 }
 
 // ランク戦かどうかを判定
@@ -1,19 +1,58 @@
 package riot
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ステータスコード別のエラー（errors.Is で判定）
+var (
+	ErrBadRequest   = errors.New("不正なリクエスト")
+	ErrUnauthorized = errors.New("APIキーが指定されていません")
+	ErrForbidden    = errors.New("APIキーが無効です")
+	ErrNotFound     = errors.New("データが見つかりません")
+	ErrRateLimited  = errors.New("レート制限を超過しました")
+	ErrServer       = errors.New("Riot APIサーバーエラー")
+)
+
+// リージョン・プラットフォームの指定が不正（リクエスト前に判定）
+var ErrUnknownRouting = errors.New("不明なリージョン・プラットフォーム")
 
 type RiotAPIError struct {
 	StatusCode int
+	Endpoint   string // リクエストしたパス
 	Message    string
-	RetryAfter string
+	RetryAfter time.Duration
 }
 
 func (e *RiotAPIError) Error() string {
-	if e.RetryAfter != "" {
-		return fmt.Sprintf("Riot API Error (status: %d, retry after: %s): %s",
-			e.StatusCode, e.RetryAfter, e.Message)
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("Riot API Error (status: %d, endpoint: %s, retry after: %v): %s",
+			e.StatusCode, e.Endpoint, e.RetryAfter, e.Message)
+	}
+	return fmt.Sprintf("Riot API Error (status: %d, endpoint: %s): %s", e.StatusCode, e.Endpoint, e.Message)
+}
+
+// ステータスコードに対応するエラーを返す（errors.Is(err, ErrNotFound) などで判定可能）
+func (e *RiotAPIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusBadRequest:
+		return ErrBadRequest
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode >= 500:
+		return ErrServer
+	default:
+		return nil
 	}
-	return fmt.Sprintf("Riot API Error (status: %d): %s", e.StatusCode, e.Message)
 }
 
 func (e *RiotAPIError) IsRateLimit() bool {
@@ -0,0 +1,148 @@
+package riot
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// デフォルトの並行取得数
+const DefaultFetchWorkers = 4
+
+// マッチ詳細1件分の取得結果
+type MatchFetchResult struct {
+	MatchID string
+	Detail  *MatchDetail
+	Err     error
+}
+
+// タイムライン1件分の取得結果
+type TimelineFetchResult struct {
+	MatchID  string
+	Timeline *MatchTimeline
+	Err      error
+}
+
+// 複数のマッチ詳細を並行取得（結果は matchIDs と同じ順序で返す）
+// 並行数は FetchWorkers で指定し、リクエスト間隔は共有の RateLimiter で制御する
+func (c *Client) FetchMatchDetailsWithContext(ctx context.Context, matchIDs []string) ([]MatchFetchResult, error) {
+	results := make([]MatchFetchResult, len(matchIDs))
+	for i, matchID := range matchIDs {
+		results[i].MatchID = matchID
+	}
+
+	err := c.fetchConcurrently(ctx, PhaseMatches, matchIDs, func(i int) (*MatchDetail, error) {
+		results[i].Detail, results[i].Err = c.GetMatchDetailWithContext(ctx, matchIDs[i])
+		return results[i].Detail, results[i].Err
+	})
+	if err != nil {
+		// 未着手の試合にもキャンセル理由を設定
+		for i := range results {
+			if results[i].Detail == nil && results[i].Err == nil {
+				results[i].Err = ctx.Err()
+			}
+		}
+		return results, err
+	}
+
+	return results, nil
+}
+
+// 複数のタイムラインを並行取得（結果は matchIDs と同じ順序で返す）
+func (c *Client) FetchMatchTimelinesWithContext(ctx context.Context, matchIDs []string) ([]TimelineFetchResult, error) {
+	results := make([]TimelineFetchResult, len(matchIDs))
+	for i, matchID := range matchIDs {
+		results[i].MatchID = matchID
+	}
+
+	err := c.fetchConcurrently(ctx, PhaseTimelines, matchIDs, func(i int) (*MatchDetail, error) {
+		results[i].Timeline, results[i].Err = c.GetMatchTimelineWithContext(ctx, matchIDs[i])
+		return nil, results[i].Err
+	})
+	if err != nil {
+		for i := range results {
+			if results[i].Timeline == nil && results[i].Err == nil {
+				results[i].Err = ctx.Err()
+			}
+		}
+		return results, err
+	}
+
+	return results, nil
+}
+
+// fetch(0)〜fetch(len(matchIDs)-1) を FetchWorkers 個のワーカーで実行し、進捗を通知する
+// fetch がエラーを返した試合は skipped として通知し、マッチ詳細を返した場合は進捗と一緒に通知する
+func (c *Client) fetchConcurrently(ctx context.Context, phase ProgressPhase, matchIDs []string, fetch func(i int) (*MatchDetail, error)) error {
+	total := len(matchIDs)
+	if total == 0 {
+		return nil
+	}
+
+	workers := c.FetchWorkers
+	if workers <= 0 {
+		workers = DefaultFetchWorkers
+	}
+	workers = min(workers, total)
+
+	startTime := time.Now()
+	c.report(ctx, ProgressEvent{Type: ProgressPhaseStart, Phase: phase, Total: total,
+		Message: fmt.Sprintf("%s取得開始（並行数: %d）", phase.Label(), workers)})
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		done    int
+		skipped int
+	)
+
+	jobs := make(chan int)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				match, err := fetch(i)
+
+				// 通知の順序が前後しないよう件数の更新と通知をまとめて行う
+				mu.Lock()
+				done++
+				if err != nil {
+					skipped++
+					c.report(ctx, ProgressEvent{Type: ProgressSkipped, Phase: phase, Fetched: done, Total: total,
+						Skipped: skipped, MatchID: matchIDs[i], Err: err})
+				}
+
+				elapsed := time.Since(startTime)
+				remaining := elapsed / time.Duration(done) * time.Duration(total-done)
+				c.report(ctx, ProgressEvent{Type: ProgressAdvance, Phase: phase, Fetched: done, Total: total,
+					Skipped: skipped, Elapsed: elapsed, ETA: remaining, MatchID: matchIDs[i], Match: match})
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for i := 0; i < total; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("処理がキャンセルされました: %w", err)
+	}
+
+	c.report(ctx, ProgressEvent{Type: ProgressPhaseDone, Phase: phase, Fetched: done, Total: total, Skipped: skipped,
+		Elapsed: time.Since(startTime),
+		Message: fmt.Sprintf("%s取得完了: %d試合を%vで処理", phase.Label(), total, time.Since(startTime).Round(time.Second))})
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package riot
+
+import (
+	"slices"
+	"time"
+)
+
+// 分析対象の試合を絞り込む条件
+// 指定したフィールドはすべて AND で評価し、ゼロ値のフィールドは条件なしとして扱う
+type MatchFilter struct {
+	Queues      []int         // 対象キューID
+	Champions   []string      // 対象チャンピオン名（ChampionName）
+	Positions   []string      // 対象ポジション（TeamPosition）
+	From        time.Time     // この時刻以降に開始した試合
+	To          time.Time     // この時刻以前に開始した試合
+	MinDuration time.Duration // 最短試合時間（リメイク除外など）
+}
+
+// ゲーム種別ごとの既定フィルタ
+var GameTypeFilters = map[string]MatchFilter{
+	"ranked": {Queues: []int{QueueSoloRanked, QueueFlexRanked}},
+	"solo":   {Queues: []int{QueueSoloRanked}},
+	"flex":   {Queues: []int{QueueFlexRanked}},
+	"normal": {Queues: []int{QueueNormalDraft, QueueNormalBlind}},
+	"aram":   {Queues: []int{QueueARAM}},
+	"arena":  {Queues: []int{QueueArena}},
+	"all":    {},
+}
+
+// 試合が条件を満たすか判定（チャンピオン・ポジションは puuid の参加者で判定）
+func (f MatchFilter) Match(match *MatchDetail, puuid string) bool {
+	if len(f.Queues) > 0 && !slices.Contains(f.Queues, match.Info.QueueID) {
+		return false
+	}
+
+	startedAt := time.UnixMilli(match.Info.GameStartTime)
+	if !f.From.IsZero() && startedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && startedAt.After(f.To) {
+		return false
+	}
+
+	if f.MinDuration > 0 && time.Duration(match.Info.GameDuration)*time.Second < f.MinDuration {
+		return false
+	}
+
+	if len(f.Champions) == 0 && len(f.Positions) == 0 {
+		return true
+	}
+
+	player := FindParticipant(match, puuid)
+	if player == nil {
+		return false
+	}
+
+	if len(f.Champions) > 0 && !slices.Contains(f.Champions, player.ChampionName) {
+		return false
+	}
+	if len(f.Positions) > 0 && !slices.Contains(f.Positions, player.TeamPosition) {
+		return false
+	}
+
+	return true
+}
+
+// match-v5 側で絞り込める条件をマッチ履歴の取得条件に変換
+// type=normal は ARAM などランク戦以外のキューもすべて含むため、ランク戦以外の複数のキューはキューごとの条件にする
+func (f MatchFilter) historyOptions(count int) []MatchHistoryOptions {
+	opts := MatchHistoryOptions{
+		StartTime: f.From,
+		EndTime:   f.To,
+		Count:     count,
+	}
+
+	switch {
+	case len(f.Queues) == 1:
+		opts.Queue = f.Queues[0]
+	case len(f.Queues) > 1 && allQueues(f.Queues, IsRankedQueue):
+		opts.Type = "ranked"
+	case len(f.Queues) > 1:
+		queries := make([]MatchHistoryOptions, len(f.Queues))
+		for i, queueID := range f.Queues {
+			queries[i] = opts
+			queries[i].Queue = queueID
+		}
+		return queries
+	}
+
+	return []MatchHistoryOptions{opts}
+}
+
+func allQueues(queues []int, pred func(int) bool) bool {
+	for _, queueID := range queues {
+		if !pred(queueID) {
+			return false
+		}
+	}
+	return true
+}
+
+// 試合の参加者から puuid のプレイヤーを探す（見つからない場合は nil）
+func FindParticipant(match *MatchDetail, puuid string) *Participant {
+	for i := range match.Info.Participants {
+		if match.Info.Participants[i].PUUID == puuid {
+			return &match.Info.Participants[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,481 @@
+package riot_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+	"github.com/MicronGit/Summoner-Analysis/internal/riot/riottest"
+)
+
+const testPUUID = "test-puuid"
+
+// メモリ上の PlayerHistoryStore
+type memoryHistoryStore struct {
+	mu        sync.Mutex
+	histories map[string]riot.PlayerHistory
+}
+
+func newMemoryHistoryStore() *memoryHistoryStore {
+	return &memoryHistoryStore{histories: make(map[string]riot.PlayerHistory)}
+}
+
+func (m *memoryHistoryStore) GetPlayerHistory(puuid, query string) (*riot.PlayerHistory, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	history, ok := m.histories[puuid+"/"+query]
+	if !ok {
+		return nil, false, nil
+	}
+	history.MatchIDs = slices.Clone(history.MatchIDs)
+	return &history, true, nil
+}
+
+func (m *memoryHistoryStore) PutPlayerHistory(history *riot.PlayerHistory) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := *history
+	stored.MatchIDs = slices.Clone(history.MatchIDs)
+	m.histories[history.PUUID+"/"+history.Query] = stored
+	return nil
+}
+
+// 1時間おきに開始した試合（番号が大きいほど新しい）
+var testEpoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func testMatchID(n int) string {
+	return fmt.Sprintf("JP1_%d", n)
+}
+
+func testGameStart(n int) time.Time {
+	return testEpoch.Add(time.Duration(n) * time.Hour)
+}
+
+func testMatch(n int) riot.MatchDetail {
+	var match riot.MatchDetail
+	match.Metadata.MatchID = testMatchID(n)
+	match.Metadata.Participants = []string{testPUUID}
+	match.Info.QueueID = riot.QueueSoloRanked
+	match.Info.GameStartTime = testGameStart(n).UnixMilli()
+	match.Info.GameDuration = 1800
+	match.Info.Participants = []riot.Participant{{PUUID: testPUUID, ChampionName: "Ahri", TeamPosition: "MIDDLE"}}
+	return match
+}
+
+// from〜to 番の試合を新しい順に並べたID
+func testMatchIDs(from, to int) riot.MatchHistory {
+	var ids riot.MatchHistory
+	for n := to; n >= from; n-- {
+		ids = append(ids, testMatchID(n))
+	}
+	return ids
+}
+
+// 1〜matches 番の試合を返すフェイクサーバーに接続したクライアント
+func newTestClient(t *testing.T, cfg riottest.Config, matches int) (*riot.Client, *riottest.Server) {
+	t.Helper()
+
+	fake, err := riottest.NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	for n := 1; n <= matches; n++ {
+		fake.AddMatch(testMatch(n))
+	}
+
+	ts := httptest.NewServer(fake)
+	t.Cleanup(ts.Close)
+
+	client := riot.NewClient("test-key", riot.RegionAsia,
+		riot.WithBaseURL(ts.URL),
+		riot.WithRetryPolicy(riot.RetryPolicy{MaxAttempts: 1}),
+	)
+	return client, fake
+}
+
+func TestSyncMatchHistory(t *testing.T) {
+	tests := []struct {
+		name    string
+		matches int                 // サーバー上の試合数
+		stored  *riot.PlayerHistory // 保存済みの履歴（nil で初回）
+		count   int
+
+		want       riot.MatchHistory // 返されるID
+		wantStored riot.MatchHistory // 保存される履歴
+		wantStart  time.Time         // 保存される境界の開始時刻
+	}{
+		{
+			name:       "初回は直近Count件",
+			matches:    10,
+			count:      5,
+			want:       testMatchIDs(6, 10),
+			wantStored: testMatchIDs(6, 10),
+		},
+		{
+			name:    "差分同期",
+			matches: 12,
+			stored: &riot.PlayerHistory{
+				MatchIDs:        testMatchIDs(1, 10),
+				LatestGameStart: testGameStart(10),
+			},
+			count:      5,
+			want:       testMatchIDs(8, 12),
+			wantStored: testMatchIDs(1, 12),
+		},
+		{
+			name:    "新しい試合なしは境界を引き継ぐ",
+			matches: 10,
+			stored: &riot.PlayerHistory{
+				MatchIDs:        testMatchIDs(1, 10),
+				LatestGameStart: testGameStart(10),
+			},
+			count:      5,
+			want:       testMatchIDs(6, 10),
+			wantStored: testMatchIDs(1, 10),
+			wantStart:  testGameStart(10),
+		},
+		{
+			name:    "Count件を超える新しい試合もページングしてつなげる",
+			matches: 250,
+			stored: &riot.PlayerHistory{
+				MatchIDs:        testMatchIDs(1, 5),
+				LatestGameStart: testGameStart(5),
+			},
+			count:      5,
+			want:       testMatchIDs(246, 250),
+			wantStored: testMatchIDs(1, 250),
+		},
+		{
+			name:    "上限までにつながらない場合は保存済みの履歴を破棄",
+			matches: 1010,
+			stored: &riot.PlayerHistory{
+				MatchIDs:        testMatchIDs(1, 5),
+				LatestGameStart: testGameStart(5),
+			},
+			count:      5,
+			want:       testMatchIDs(1006, 1010),
+			wantStored: testMatchIDs(11, 1010),
+		},
+		{
+			name:    "境界が不明で直近Count件とつながらない場合は破棄",
+			matches: 20,
+			stored: &riot.PlayerHistory{
+				MatchIDs: testMatchIDs(1, 10),
+			},
+			count:      5,
+			want:       testMatchIDs(16, 20),
+			wantStored: testMatchIDs(16, 20),
+		},
+		{
+			name:    "境界が不明でも直近Count件とつながれば統合",
+			matches: 12,
+			stored: &riot.PlayerHistory{
+				MatchIDs: testMatchIDs(1, 10),
+			},
+			count:      5,
+			want:       testMatchIDs(8, 12),
+			wantStored: testMatchIDs(1, 12),
+		},
+		{
+			name:    "保存済みがCount件未満なら直近Count件と統合",
+			matches: 6,
+			stored: &riot.PlayerHistory{
+				MatchIDs:        testMatchIDs(1, 3),
+				LatestGameStart: testGameStart(3),
+			},
+			count:      5,
+			want:       testMatchIDs(2, 6),
+			wantStored: testMatchIDs(1, 6),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newTestClient(t, riottest.Config{}, tt.matches)
+
+			histories := newMemoryHistoryStore()
+			client.HistoryStore = histories
+
+			opts := riot.MatchHistoryOptions{Queue: riot.QueueSoloRanked, Count: tt.count}
+			if tt.stored != nil {
+				// 保存済みの履歴を同じ条件のキーで登録するため、一度同期してから上書きする
+				if _, err := client.SyncMatchHistoryWithContext(context.Background(), testPUUID, opts); err != nil {
+					t.Fatalf("初回の同期: %v", err)
+				}
+				history, _, _ := histories.GetPlayerHistory(testPUUID, queryKey(t, histories))
+				history.MatchIDs = tt.stored.MatchIDs
+				history.LatestGameStart = tt.stored.LatestGameStart
+				histories.PutPlayerHistory(history)
+			}
+
+			got, err := client.SyncMatchHistoryWithContext(context.Background(), testPUUID, opts)
+			if err != nil {
+				t.Fatalf("SyncMatchHistoryWithContext: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", summarizeIDs(got), summarizeIDs(tt.want))
+			}
+
+			history, ok, _ := histories.GetPlayerHistory(testPUUID, queryKey(t, histories))
+			if !ok {
+				t.Fatal("履歴が保存されていない")
+			}
+			if !slices.Equal(history.MatchIDs, tt.wantStored) {
+				t.Errorf("保存された履歴 %v, want %v", summarizeIDs(history.MatchIDs), summarizeIDs(tt.wantStored))
+			}
+			if !history.LatestGameStart.Equal(tt.wantStart) {
+				t.Errorf("LatestGameStart = %v, want %v", history.LatestGameStart, tt.wantStart)
+			}
+		})
+	}
+}
+
+// 分析でマッチ詳細を取得すると、次回の差分同期の境界が記録される
+func TestGetPlayerAnalysisRecordsLatestGameStart(t *testing.T) {
+	client, fake := newTestClient(t, riottest.Config{}, 8)
+
+	histories := newMemoryHistoryStore()
+	client.HistoryStore = histories
+
+	account := &riot.Account{PUUID: testPUUID}
+	opts, _ := riot.AnalysisOptionsForGameType("solo", 5)
+
+	if _, err := client.GetPlayerAnalysis(context.Background(), account, opts); err != nil {
+		t.Fatalf("GetPlayerAnalysis: %v", err)
+	}
+
+	history, _, _ := histories.GetPlayerHistory(testPUUID, queryKey(t, histories))
+	if want := testGameStart(8); !history.LatestGameStart.Equal(want) {
+		t.Fatalf("LatestGameStart = %v, want %v", history.LatestGameStart, want)
+	}
+
+	// 2回目は差分同期になり、マッチIDの一覧は新しい試合までの1ページで済む
+	fake.AddMatch(testMatch(9))
+	before := fake.Requests()
+
+	summary, err := client.GetPlayerAnalysis(context.Background(), account, opts)
+	if err != nil {
+		t.Fatalf("GetPlayerAnalysis: %v", err)
+	}
+	if summary.TotalMatches != 5 || summary.MatchHistory[0].Metadata.MatchID != testMatchID(9) {
+		t.Errorf("最新の試合 = %s（%d試合）, want %s", summary.MatchHistory[0].Metadata.MatchID, summary.TotalMatches, testMatchID(9))
+	}
+
+	// マッチID 1ページ + リーグ・マスタリー + マッチ詳細5件
+	if got, want := fake.Requests()-before, 1+2+5; got != want {
+		t.Errorf("リクエスト数 = %d, want %d", got, want)
+	}
+
+	history, _, _ = histories.GetPlayerHistory(testPUUID, queryKey(t, histories))
+	if want := testGameStart(9); !history.LatestGameStart.Equal(want) {
+		t.Errorf("LatestGameStart = %v, want %v", history.LatestGameStart, want)
+	}
+}
+
+// 保存済みの唯一の条件キー
+func queryKey(t *testing.T, m *memoryHistoryStore) string {
+	t.Helper()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.histories) != 1 {
+		t.Fatalf("保存された履歴が %d 件", len(m.histories))
+	}
+	for _, history := range m.histories {
+		return history.Query
+	}
+	return ""
+}
+
+// 失敗時の表示用（先頭・末尾と件数）
+func summarizeIDs(ids riot.MatchHistory) string {
+	if len(ids) <= 4 {
+		return fmt.Sprint([]string(ids))
+	}
+	return fmt.Sprintf("[%s %s ... %s]（%d件）", ids[0], ids[1], ids[len(ids)-1], len(ids))
+}
+
+// n 番の試合の30分後に開始した ARAM の試合
+func testARAMMatch(n int) riot.MatchDetail {
+	match := testMatch(n)
+	match.Metadata.MatchID = fmt.Sprintf("JP1_ARAM_%d", n)
+	match.Info.QueueID = riot.QueueARAM
+	match.Info.GameStartTime = testGameStart(n).Add(30 * time.Minute).UnixMilli()
+	return match
+}
+
+func TestGetMatchHistory(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      riot.MatchHistoryOptions
+		want      riot.MatchHistory
+		wantPages int
+	}{
+		{
+			name:      "キューで絞り込んで100件を超えてページング",
+			opts:      riot.MatchHistoryOptions{Queue: riot.QueueSoloRanked, Count: 150},
+			want:      testMatchIDs(101, 250),
+			wantPages: 2,
+		},
+		{
+			name:      "種別で絞り込み",
+			opts:      riot.MatchHistoryOptions{Type: "ranked", Count: 30},
+			want:      testMatchIDs(221, 250),
+			wantPages: 1,
+		},
+		{
+			name:      "条件に合う試合が尽きたら終了",
+			opts:      riot.MatchHistoryOptions{Queue: riot.QueueSoloRanked, Count: 300},
+			want:      testMatchIDs(1, 250),
+			wantPages: 3,
+		},
+		{
+			name:      "ちょうどページ境界で尽きる",
+			opts:      riot.MatchHistoryOptions{Queue: riot.QueueSoloRanked, StartTime: testGameStart(51), Count: 300},
+			want:      testMatchIDs(51, 250),
+			wantPages: 3,
+		},
+		{
+			name: "期間指定",
+			opts: riot.MatchHistoryOptions{
+				Queue:     riot.QueueSoloRanked,
+				StartTime: testGameStart(10),
+				EndTime:   testGameStart(20),
+				Count:     100,
+			},
+			want:      testMatchIDs(10, 20),
+			wantPages: 1,
+		},
+		{
+			name:      "開始位置",
+			opts:      riot.MatchHistoryOptions{Queue: riot.QueueSoloRanked, Start: 100, Count: 120},
+			want:      testMatchIDs(31, 150),
+			wantPages: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, fake := newTestClient(t, riottest.Config{}, 250)
+
+			// ソロランクの間に ARAM を挟み、絞り込まないと件数が足りなくなるようにする
+			for n := 1; n <= 250; n++ {
+				fake.AddMatch(testARAMMatch(n))
+			}
+
+			got, err := client.GetMatchHistoryWithContext(context.Background(), testPUUID, tt.opts)
+			if err != nil {
+				t.Fatalf("GetMatchHistoryWithContext: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", summarizeIDs(got), summarizeIDs(tt.want))
+			}
+			if pages := fake.Requests(); pages != tt.wantPages {
+				t.Errorf("リクエスト数 = %d, want %d", pages, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestGetPlayerAnalysisMultipleQueues(t *testing.T) {
+	// n 番の試合のキュー（ソロ・ドラフト・ブラインド・フレックスの順）
+	queues := []int{riot.QueueSoloRanked, riot.QueueNormalDraft, riot.QueueNormalBlind, riot.QueueFlexRanked}
+
+	tests := []struct {
+		name         string
+		gameType     string
+		count        int
+		want         riot.MatchHistory // 省略した場合は件数のみ確認
+		wantGames    int
+		wantRequests int // マッチIDのページ + リーグ・マスタリー + マッチ詳細
+		wantQueries  []string
+	}{
+		{
+			name:         "ノーマルはキューごとに取得して統合",
+			gameType:     "normal",
+			count:        6,
+			want:         riot.MatchHistory{testMatchID(30), testMatchID(29), testMatchID(26), testMatchID(25), testMatchID(22), testMatchID(21)},
+			wantGames:    6,
+			wantRequests: 2 + 2 + 6,
+			wantQueries:  []string{"queue-400", "queue-430"},
+		},
+		{
+			name:         "条件に合う試合が足りない",
+			gameType:     "normal",
+			count:        100,
+			wantGames:    16,
+			wantRequests: 2 + 2 + 16,
+			wantQueries:  []string{"queue-400", "queue-430"},
+		},
+		{
+			name:         "ランク戦は種別で取得",
+			gameType:     "ranked",
+			count:        6,
+			want:         riot.MatchHistory{testMatchID(28), testMatchID(27), testMatchID(24), testMatchID(23), testMatchID(20), testMatchID(19)},
+			wantGames:    6,
+			wantRequests: 1 + 2 + 6,
+			wantQueries:  []string{"type-ranked"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, fake := newTestClient(t, riottest.Config{}, 0)
+			for n := 1; n <= 30; n++ {
+				match := testMatch(n)
+				match.Info.QueueID = queues[n%len(queues)]
+				fake.AddMatch(match)
+
+				// type=normal で返るが分析対象ではない ARAM
+				fake.AddMatch(testARAMMatch(n))
+			}
+
+			histories := newMemoryHistoryStore()
+			client.HistoryStore = histories
+
+			opts, _ := riot.AnalysisOptionsForGameType(tt.gameType, tt.count)
+			summary, err := client.GetPlayerAnalysis(context.Background(), &riot.Account{PUUID: testPUUID}, opts)
+			if err != nil {
+				t.Fatalf("GetPlayerAnalysis: %v", err)
+			}
+
+			var got riot.MatchHistory
+			for _, match := range summary.MatchHistory {
+				if !slices.Contains(opts.Filter.Queues, match.Info.QueueID) {
+					t.Errorf("%s: 対象外のキュー %d", match.Metadata.MatchID, match.Info.QueueID)
+				}
+				got = append(got, match.Metadata.MatchID)
+			}
+			if tt.want != nil && !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", summarizeIDs(got), summarizeIDs(tt.want))
+			}
+			if len(got) != tt.wantGames {
+				t.Errorf("got %d試合, want %d", len(got), tt.wantGames)
+			}
+			if requests := fake.Requests(); requests != tt.wantRequests {
+				t.Errorf("リクエスト数 = %d, want %d", requests, tt.wantRequests)
+			}
+
+			// 条件ごとに履歴と差分同期の境界を保存する
+			var queries []string
+			for key, history := range histories.histories {
+				queries = append(queries, history.Query)
+				if history.LatestGameStart.IsZero() {
+					t.Errorf("%s: LatestGameStart が記録されていない", key)
+				}
+			}
+			slices.Sort(queries)
+			if !slices.Equal(queries, tt.wantQueries) {
+				t.Errorf("保存された条件 = %v, want %v", queries, tt.wantQueries)
+			}
+		})
+	}
+}
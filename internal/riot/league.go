@@ -0,0 +1,143 @@
+package riot
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ランクのキュー種別（league-v4 の queueType）
+const (
+	QueueTypeSolo = "RANKED_SOLO_5x5"
+	QueueTypeFlex = "RANKED_FLEX_SR"
+)
+
+// リーグエントリー（league-v4）
+type LeagueEntry struct {
+	LeagueID     string      `json:"leagueId"`
+	PUUID        string      `json:"puuid"`
+	QueueType    string      `json:"queueType"`
+	Tier         string      `json:"tier"` // IRON〜CHALLENGER
+	Rank         string      `json:"rank"` // I〜IV
+	LeaguePoints int         `json:"leaguePoints"`
+	Wins         int         `json:"wins"`
+	Losses       int         `json:"losses"`
+	HotStreak    bool        `json:"hotStreak"`
+	Veteran      bool        `json:"veteran"`
+	FreshBlood   bool        `json:"freshBlood"`
+	Inactive     bool        `json:"inactive"`
+	MiniSeries   *MiniSeries `json:"miniSeries,omitempty"` // 昇格戦中のみ
+}
+
+// 昇格戦の進行状況
+type MiniSeries struct {
+	Losses   int    `json:"losses"`
+	Progress string `json:"progress"` // 例: "WLN"（N は未消化）
+	Target   int    `json:"target"`
+	Wins     int    `json:"wins"`
+}
+
+// ランク戦の勝率（%）
+func (e *LeagueEntry) WinRate() float64 {
+	games := e.Wins + e.Losses
+	if games == 0 {
+		return 0
+	}
+	return float64(e.Wins) / float64(games) * 100
+}
+
+// キュー種別に対応するエントリーを取得（未ランクの場合は nil）
+func FindLeagueEntry(entries []LeagueEntry, queueType string) *LeagueEntry {
+	for i := range entries {
+		if entries[i].QueueType == queueType {
+			return &entries[i]
+		}
+	}
+	return nil
+}
+
+// PUUID からリーグエントリーを取得（未ランクの場合は空）
+func (c *Client) GetLeagueEntriesByPUUIDWithContext(ctx context.Context, puuid string) ([]LeagueEntry, error) {
+	routing, err := c.platformRouting()
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/lol/league/v4/entries/by-puuid/%s", url.PathEscape(puuid))
+
+	var entries []LeagueEntry
+	if err := c.getJSON(ctx, routing, MethodLeagueEntriesByPUUID, endpoint, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ある時点のランク（LP 推移の記録用）
+type LeagueSnapshot struct {
+	PUUID   string        `json:"puuid"`
+	TakenAt time.Time     `json:"takenAt"`
+	Entries []LeagueEntry `json:"entries"`
+}
+
+// 現在のランクを取得し、前回から変化していればスナップショットとして保存
+func (c *Client) RecordLeagueSnapshotWithContext(ctx context.Context, puuid string) ([]LeagueEntry, error) {
+	entries, err := c.GetLeagueEntriesByPUUIDWithContext(ctx, puuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.LeagueHistoryStore == nil {
+		return entries, nil
+	}
+
+	snapshots, err := c.LeagueHistoryStore.GetLeagueSnapshots(puuid)
+	if err != nil {
+		c.report(ctx, ProgressEvent{Type: ProgressWarning, Phase: PhaseLeague, Message: "ランク履歴の読み込みに失敗", Err: err})
+		return entries, nil
+	}
+
+	// 試合をしていなければ LP も勝敗数も変わらないため保存しない
+	if len(snapshots) > 0 && sameLeagueState(snapshots[len(snapshots)-1].Entries, entries) {
+		return entries, nil
+	}
+
+	snapshot := &LeagueSnapshot{
+		PUUID:   puuid,
+		TakenAt: time.Now(),
+		Entries: entries,
+	}
+	if err := c.LeagueHistoryStore.PutLeagueSnapshot(snapshot); err != nil {
+		c.report(ctx, ProgressEvent{Type: ProgressWarning, Phase: PhaseLeague, Message: "ランク履歴の保存に失敗", Err: err})
+	}
+
+	return entries, nil
+}
+
+// 保存済みのランク履歴（古い順、ストア未設定の場合は nil）
+func (c *Client) GetLeagueHistory(puuid string) ([]LeagueSnapshot, error) {
+	if c.LeagueHistoryStore == nil {
+		return nil, nil
+	}
+	return c.LeagueHistoryStore.GetLeagueSnapshots(puuid)
+}
+
+// キューごとのランク・LP・勝敗数がすべて一致するか
+func sameLeagueState(a, b []LeagueEntry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for _, entry := range a {
+		other := FindLeagueEntry(b, entry.QueueType)
+		if other == nil ||
+			other.Tier != entry.Tier ||
+			other.Rank != entry.Rank ||
+			other.LeaguePoints != entry.LeaguePoints ||
+			other.Wins != entry.Wins ||
+			other.Losses != entry.Losses {
+			return false
+		}
+	}
+	return true
+}
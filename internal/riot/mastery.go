@@ -0,0 +1,43 @@
+package riot
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// チャンピオンマスタリー（champion-mastery-v4）
+type ChampionMastery struct {
+	PUUID                        string `json:"puuid"`
+	ChampionID                   int    `json:"championId"`
+	ChampionLevel                int    `json:"championLevel"`
+	ChampionPoints               int    `json:"championPoints"`
+	LastPlayTime                 int64  `json:"lastPlayTime"` // ミリ秒
+	ChampionPointsSinceLastLevel int    `json:"championPointsSinceLastLevel"`
+	ChampionPointsUntilNextLevel int    `json:"championPointsUntilNextLevel"`
+	MarkRequiredForNextLevel     int    `json:"markRequiredForNextLevel"`
+	TokensEarned                 int    `json:"tokensEarned"`
+	ChampionSeasonMilestone      int    `json:"championSeasonMilestone"`
+}
+
+// 最後にプレイした時刻
+func (m *ChampionMastery) LastPlayed() time.Time {
+	return time.UnixMilli(m.LastPlayTime)
+}
+
+// 全チャンピオンのマスタリーを取得（ポイントの多い順）
+func (c *Client) GetChampionMasteriesWithContext(ctx context.Context, puuid string) ([]ChampionMastery, error) {
+	routing, err := c.platformRouting()
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/lol/champion-mastery/v4/champion-masteries/by-puuid/%s", url.PathEscape(puuid))
+
+	var masteries []ChampionMastery
+	if err := c.getJSON(ctx, routing, MethodChampionMasteries, endpoint, &masteries); err != nil {
+		return nil, err
+	}
+
+	return masteries, nil
+}
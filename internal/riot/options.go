@@ -0,0 +1,88 @@
+package riot
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Riot API の既定のベースURL（{region} はルーティング値に置換）
+const DefaultBaseURL = "https://{region}.api.riotgames.com"
+
+// リトライ方針
+type RetryPolicy struct {
+	MaxAttempts       int           // 最大試行回数（初回を含む）
+	NetworkErrorDelay time.Duration // ネットワークエラー時の待機時間
+	ServerErrorDelay  time.Duration // 5xx エラー時の待機時間（試行ごとに倍数で増加）
+	RateLimitDelay    time.Duration // Retry-After がない 429 の待機時間
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:       3,
+		NetworkErrorDelay: time.Second,
+		ServerErrorDelay:  time.Second,
+		RateLimitDelay:    2 * time.Second,
+	}
+}
+
+// NewClient のオプション
+type Option func(*Client)
+
+// ベースURLのテンプレートを指定（ローカルのモックやプロキシ向け）
+// 例: "http://localhost:9090"、"https://gateway.example.com/riot/{region}"
+func WithBaseURL(template string) Option {
+	return func(c *Client) {
+		c.BaseURL = strings.TrimSuffix(template, "/")
+	}
+}
+
+// HTTP トランスポートを指定（記録用プロキシやテスト用のスタブなど）
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.HTTPClient.Transport = transport
+	}
+}
+
+// プラットフォーム（jp1 / kr / na1 など）を指定（match-v5 のリージョンもこれから決まる）
+func WithPlatform(platform string) Option {
+	return func(c *Client) {
+		c.Platform = platform
+	}
+}
+
+// 進捗・警告の通知先を指定
+func WithProgress(reporter ProgressReporter) Option {
+	return func(c *Client) {
+		c.Progress = reporter
+	}
+}
+
+// User-Agent ヘッダーを指定
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.UserAgent = userAgent
+	}
+}
+
+// 1リクエストあたりのタイムアウトを指定
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.HTTPClient.Timeout = timeout
+	}
+}
+
+// リトライ方針を指定
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		if policy.MaxAttempts <= 0 {
+			policy.MaxAttempts = 1
+		}
+		c.Retry = policy
+	}
+}
+
+// ルーティング値（asia / jp1 など）に対応するベースURL
+func (c *Client) baseURL(routing string) string {
+	return strings.ReplaceAll(c.BaseURL, "{region}", routing)
+}
@@ -0,0 +1,162 @@
+package riot
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// 進捗イベントの種類
+type ProgressEventType string
+
+const (
+	ProgressPhaseStart    ProgressEventType = "phase-start"
+	ProgressAdvance       ProgressEventType = "progress" // 1件取得するごと
+	ProgressPhaseDone     ProgressEventType = "phase-done"
+	ProgressSkipped       ProgressEventType = "skipped" // 取得に失敗し分析から除外した試合
+	ProgressRateLimitWait ProgressEventType = "rate-limit-wait"
+	ProgressRetry         ProgressEventType = "retry" // 429・5xx・ネットワークエラーによる再試行
+	ProgressInfo          ProgressEventType = "info"
+	ProgressWarning       ProgressEventType = "warning" // 処理は続行できる失敗（キャッシュの読み書きなど）
+)
+
+// 処理段階
+type ProgressPhase string
+
+const (
+	PhaseMatchIDs  ProgressPhase = "match-ids"
+	PhaseLeague    ProgressPhase = "league"
+	PhaseMastery   ProgressPhase = "mastery"
+	PhaseMatches   ProgressPhase = "matches"
+	PhaseTimelines ProgressPhase = "timelines"
+)
+
+var phaseLabels = map[ProgressPhase]string{
+	PhaseMatchIDs:  "マッチ履歴",
+	PhaseLeague:    "ランク情報",
+	PhaseMastery:   "チャンピオンマスタリー",
+	PhaseMatches:   "マッチ詳細",
+	PhaseTimelines: "タイムライン",
+}
+
+// 表示用の名前
+func (p ProgressPhase) Label() string {
+	if label, ok := phaseLabels[p]; ok {
+		return label
+	}
+	return string(p)
+}
+
+// 進捗イベント（Type によって使われるフィールドが異なる）
+type ProgressEvent struct {
+	Type  ProgressEventType
+	Phase ProgressPhase // レート制限待ち・再試行など処理段階に依らないイベントは空
+
+	// ProgressPhaseStart / ProgressAdvance / ProgressPhaseDone
+	Fetched int           // 処理済みの件数（失敗を含む）
+	Total   int           // 全体の件数
+	Skipped int           // 処理済みのうち失敗した件数
+	Elapsed time.Duration // 段階の開始からの経過時間
+	ETA     time.Duration // 推定残り時間（ProgressAdvance のみ）
+
+	// ProgressRateLimitWait / ProgressRetry
+	Wait    time.Duration
+	Routing string // asia / jp1 など
+	Method  string
+	Attempt int // 再試行の場合の失敗した試行（1始まり）
+
+	MatchID string
+	Message string
+	Err     error
+
+	// 取得したマッチ詳細（PhaseMatches の ProgressAdvance のみ、途中集計用で JSON には含めない）
+	Match *MatchDetail
+}
+
+// JSON（Server-Sent Events などでの配信用、時間は秒単位）
+func (e ProgressEvent) MarshalJSON() ([]byte, error) {
+	type progressJSON struct {
+		Type           ProgressEventType `json:"type"`
+		Phase          ProgressPhase     `json:"phase,omitempty"`
+		Fetched        int               `json:"fetched"`
+		Total          int               `json:"total"`
+		Skipped        int               `json:"skipped"`
+		ElapsedSeconds float64           `json:"elapsedSeconds"`
+		ETASeconds     float64           `json:"etaSeconds"`
+		WaitSeconds    float64           `json:"waitSeconds,omitempty"`
+		Routing        string            `json:"routing,omitempty"`
+		Method         string            `json:"method,omitempty"`
+		Attempt        int               `json:"attempt,omitempty"`
+		MatchID        string            `json:"matchId,omitempty"`
+		Message        string            `json:"message,omitempty"`
+		Error          string            `json:"error,omitempty"`
+	}
+
+	v := progressJSON{
+		Type:           e.Type,
+		Phase:          e.Phase,
+		Fetched:        e.Fetched,
+		Total:          e.Total,
+		Skipped:        e.Skipped,
+		ElapsedSeconds: e.Elapsed.Seconds(),
+		ETASeconds:     e.ETA.Seconds(),
+		WaitSeconds:    e.Wait.Seconds(),
+		Routing:        e.Routing,
+		Method:         e.Method,
+		Attempt:        e.Attempt,
+		MatchID:        e.MatchID,
+		Message:        e.Message,
+	}
+	if e.Err != nil {
+		v.Error = e.Err.Error()
+	}
+	return json.Marshal(v)
+}
+
+// 進捗の通知先（複数の goroutine から呼ばれるため、実装側で排他が必要な場合は行うこと）
+type ProgressReporter interface {
+	Report(event ProgressEvent)
+}
+
+// 関数を ProgressReporter として使う
+type ProgressFunc func(event ProgressEvent)
+
+func (f ProgressFunc) Report(event ProgressEvent) {
+	f(event)
+}
+
+type progressKey struct{}
+
+// リクエスト単位の通知先を設定（Client.Progress より優先される）
+// 共有のクライアントを使うサーバーで、進捗を呼び出し元ごとに配信する場合に使う
+func ContextWithProgress(ctx context.Context, reporter ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressKey{}, reporter)
+}
+
+func progressFromContext(ctx context.Context) ProgressReporter {
+	reporter, _ := ctx.Value(progressKey{}).(ProgressReporter)
+	return reporter
+}
+
+// コンテキストの通知先、なければ Client.Progress（どちらもない場合は nil）
+func (c *Client) progressReporter(ctx context.Context) ProgressReporter {
+	if reporter := progressFromContext(ctx); reporter != nil {
+		return reporter
+	}
+	return c.Progress
+}
+
+// 進捗を通知（通知先がない場合は何もしない）
+func (c *Client) report(ctx context.Context, event ProgressEvent) {
+	if reporter := c.progressReporter(ctx); reporter != nil {
+		reporter.Report(event)
+	}
+}
+
+// RateLimiter の待機を通知できるよう、通知先をコンテキストに設定
+func (c *Client) progressContext(ctx context.Context) context.Context {
+	if progressFromContext(ctx) == nil && c.Progress != nil {
+		return ContextWithProgress(ctx, c.Progress)
+	}
+	return ctx
+}
@@ -2,93 +2,135 @@ package riot
 
 import (
 	"context"
-	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
 
+// エンドポイント（メソッド）ごとのレート制限キー
+const (
+	MethodAccountByRiotID = "account-v1.by-riot-id"
+	MethodMatchIDs        = "match-v5.ids"
+	MethodMatchDetail     = "match-v5.match"
+	MethodMatchTimeline   = "match-v5.timeline"
+
+	MethodLeagueEntriesByPUUID = "league-v4.entries-by-puuid"
+	MethodChampionMasteries    = "champion-mastery-v4.by-puuid"
+	MethodActiveGame           = "spectator-v5.active-games"
+)
+
+// 1つの時間窓のレート制限
+type rateBucket struct {
+	limit  int
+	window time.Duration
+	count  int       // 現在の時間窓での使用数
+	reset  time.Time // 現在の時間窓の終了時刻
+}
+
+// アプリケーション全体、またはメソッド単位の制限
+type rateLimitGroup struct {
+	buckets      []*rateBucket
+	blockedUntil time.Time // 429 受信時の Retry-After による停止期限
+}
+
+// ルーティング値ごとの制限
+// Riot API の制限は asia / americas / europe / sea や jp1 / na1 などのルーティング値ごとに独立している
+type routingLimits struct {
+	app     *rateLimitGroup
+	methods map[string]*rateLimitGroup
+}
+
 // レート制限管理
+// 初期値は開発者キーの制限（20 requests/1s, 100 requests/2min）とし、
+// レスポンスの X-App-Rate-Limit / X-Method-Rate-Limit ヘッダーに合わせて調整する
 type RateLimiter struct {
-	// 短期間制限: 20 requests per second
-	shortTermTokens int
-	shortTermLimit  int
-	shortTermWindow time.Duration
-	shortTermReset  time.Time
-
-	// 長期間制限: 100 requests per 2 minutes
-	longTermTokens int
-	longTermLimit  int
-	longTermWindow time.Duration
-	longTermReset  time.Time
+	routings map[string]*routingLimits
 
 	mu sync.Mutex
 }
 
+// 現在の制限状況
+type RateLimitStatus struct {
+	Routing   string        `json:"routing"`
+	Scope     string        `json:"scope"` // "app" またはメソッドキー
+	Limit     int           `json:"limit"`
+	Window    time.Duration `json:"window"`
+	Available int           `json:"available"`
+	Reset     time.Time     `json:"reset"`
+}
+
 func NewRateLimiter() *RateLimiter {
-	now := time.Now()
 	return &RateLimiter{
-		shortTermTokens: 20,
-		shortTermLimit:  20,
-		shortTermWindow: time.Second,
-		shortTermReset:  now.Add(time.Second),
+		routings: make(map[string]*routingLimits),
+	}
+}
+
+// ルーティング値の制限を取得（初回は開発者キーの制限で作成）
+func (rl *RateLimiter) routingLocked(routing string, now time.Time) *routingLimits {
+	limits, ok := rl.routings[routing]
+	if !ok {
+		limits = &routingLimits{
+			app: &rateLimitGroup{
+				buckets: []*rateBucket{
+					{limit: 20, window: time.Second, reset: now.Add(time.Second)},
+					{limit: 100, window: 2 * time.Minute, reset: now.Add(2 * time.Minute)},
+				},
+			},
+			methods: make(map[string]*rateLimitGroup),
+		}
+		rl.routings[routing] = limits
+	}
+	return limits
+}
 
-		longTermTokens: 100,
-		longTermLimit:  100,
-		longTermWindow: 2 * time.Minute,
-		longTermReset:  now.Add(2 * time.Minute),
+// メソッドの制限を取得（未作成の場合は作成）
+func (h *routingLimits) method(method string) *rateLimitGroup {
+	group, ok := h.methods[method]
+	if !ok {
+		group = &rateLimitGroup{}
+		h.methods[method] = group
 	}
+	return group
 }
 
 // リクエスト許可を待機（ブロッキング）
-func (rl *RateLimiter) Wait(ctx context.Context) error {
+func (rl *RateLimiter) Wait(ctx context.Context, routing, method string) error {
 	for {
 		// mutexをロックして状態をチェック
 		rl.mu.Lock()
 
 		now := time.Now()
-
-		// トークンをリセット（時間窓が過ぎた場合）
-		if now.After(rl.shortTermReset) {
-			rl.shortTermTokens = rl.shortTermLimit
-			rl.shortTermReset = now.Add(rl.shortTermWindow)
-		}
-
-		if now.After(rl.longTermReset) {
-			rl.longTermTokens = rl.longTermLimit
-			rl.longTermReset = now.Add(rl.longTermWindow)
+		limits := rl.routingLocked(routing, now)
+		groups := []*rateLimitGroup{limits.app}
+		if group, ok := limits.methods[method]; ok {
+			groups = append(groups, group)
 		}
 
-		// 利用可能なトークンがある場合
-		if rl.shortTermTokens > 0 && rl.longTermTokens > 0 {
-			// トークンを消費
-			rl.shortTermTokens--
-			rl.longTermTokens--
-			rl.mu.Unlock() // ここでアンロック
-			return nil
-		}
-
-		// 利用可能なトークンがない場合は待機時間を計算
+		// すべての制限に空きがあればトークンを消費
 		var waitTime time.Duration
-
-		if rl.shortTermTokens <= 0 {
-			waitTime = rl.shortTermReset.Sub(now)
-		}
-
-		if rl.longTermTokens <= 0 {
-			longWaitTime := rl.longTermReset.Sub(now)
-			if longWaitTime > waitTime {
-				waitTime = longWaitTime
+		for _, group := range groups {
+			if wait := group.waitTime(now); wait > waitTime {
+				waitTime = wait
 			}
 		}
 
-		// 待機時間が0以下の場合は少し待つ
-		if waitTime <= 0 {
-			waitTime = 100 * time.Millisecond
+		if waitTime == 0 {
+			for _, group := range groups {
+				group.consume()
+			}
+			rl.mu.Unlock() // ここでアンロック
+			return nil
 		}
 
 		rl.mu.Unlock() // 待機前にアンロック
 
-		fmt.Printf("レート制限に達しました。%v 待機中...\n", waitTime.Round(time.Second))
+		if reporter := progressFromContext(ctx); reporter != nil {
+			reporter.Report(ProgressEvent{Type: ProgressRateLimitWait, Wait: waitTime, Routing: routing, Method: method})
+		}
 
 		// ノンブロッキングで待機（コンテキストキャンセル対応）
 		select {
@@ -101,25 +143,97 @@ func (rl *RateLimiter) Wait(ctx context.Context) error {
 	}
 }
 
-// 現在の制限状況を取得
-func (rl *RateLimiter) GetStatus() (shortAvailable, longAvailable int, shortReset, longReset time.Time) {
+// レスポンスヘッダーから実際の制限値と使用数を反映
+func (rl *RateLimiter) Update(routing, method string, header http.Header) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	limits := rl.routingLocked(routing, now)
+
+	if appLimits := parseRateLimitHeader(header.Get("X-App-Rate-Limit")); len(appLimits) > 0 {
+		limits.app.resize(appLimits, parseRateLimitHeader(header.Get("X-App-Rate-Limit-Count")), now)
+	}
+
+	if methodLimits := parseRateLimitHeader(header.Get("X-Method-Rate-Limit")); len(methodLimits) > 0 {
+		limits.method(method).resize(methodLimits, parseRateLimitHeader(header.Get("X-Method-Rate-Limit-Count")), now)
+	}
+}
+
+// 429 受信時に X-Rate-Limit-Type に応じて該当する制限を Retry-After の間停止
+func (rl *RateLimiter) Block(routing, method string, header http.Header, retryAfter time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	limits := rl.routingLocked(routing, now)
+	until := now.Add(retryAfter)
+
+	switch header.Get("X-Rate-Limit-Type") {
+	case "application":
+		limits.app.block(until)
+	case "method":
+		limits.method(method).block(until)
+	default:
+		// service（基盤側の制限）やヘッダーなしの場合はこのリクエストの再試行のみ待機
+	}
+}
+
+// 現在の制限状況をルーティング値・制限ごとに取得
+func (rl *RateLimiter) GetStatus() []RateLimitStatus {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
 	now := time.Now()
 
-	// 期限切れチェック
-	if now.After(rl.shortTermReset) {
-		rl.shortTermTokens = rl.shortTermLimit
-		rl.shortTermReset = now.Add(rl.shortTermWindow)
+	var status []RateLimitStatus
+	for _, routing := range slices.Sorted(maps.Keys(rl.routings)) {
+		limits := rl.routings[routing]
+
+		status = append(status, limits.app.status(routing, "app", now)...)
+		for _, method := range slices.Sorted(maps.Keys(limits.methods)) {
+			status = append(status, limits.methods[method].status(routing, method, now)...)
+		}
+	}
+
+	return status
+}
+
+// 最も長い時間窓で残っているリクエスト数（アプリとメソッドの制限の小さい方）
+// 短い時間窓は待機すれば数秒で回復するため、まとまった数のリクエストを始める前の見積もりに使う
+func (rl *RateLimiter) Budget(routing, method string) int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	limits := rl.routingLocked(routing, now)
+	groups := []*rateLimitGroup{limits.app}
+	if group, ok := limits.methods[method]; ok {
+		groups = append(groups, group)
 	}
 
-	if now.After(rl.longTermReset) {
-		rl.longTermTokens = rl.longTermLimit
-		rl.longTermReset = now.Add(rl.longTermWindow)
+	budget := -1
+	for _, group := range groups {
+		var longest *rateBucket
+		for _, bucket := range group.buckets {
+			if longest == nil || bucket.window > longest.window {
+				longest = bucket
+			}
+		}
+		if longest == nil {
+			continue
+		}
+
+		available := longest.limit
+		if now.Before(longest.reset) {
+			available = max(longest.limit-longest.count, 0)
+		}
+		if budget < 0 || available < budget {
+			budget = available
+		}
 	}
 
-	return rl.shortTermTokens, rl.longTermTokens, rl.shortTermReset, rl.longTermReset
+	return max(budget, 0)
 }
 
 // トークンの強制リセット（テスト用）
@@ -128,8 +242,133 @@ func (rl *RateLimiter) Reset() {
 	defer rl.mu.Unlock()
 
 	now := time.Now()
-	rl.shortTermTokens = rl.shortTermLimit
-	rl.shortTermReset = now.Add(rl.shortTermWindow)
-	rl.longTermTokens = rl.longTermLimit
-	rl.longTermReset = now.Add(rl.longTermWindow)
+	for _, limits := range rl.routings {
+		for _, group := range append([]*rateLimitGroup{limits.app}, slices.Collect(maps.Values(limits.methods))...) {
+			group.blockedUntil = time.Time{}
+			for _, bucket := range group.buckets {
+				bucket.count = 0
+				bucket.reset = now.Add(bucket.window)
+			}
+		}
+	}
+}
+
+// 次のリクエストまでに必要な待機時間（0なら即時実行可能）
+func (g *rateLimitGroup) waitTime(now time.Time) time.Duration {
+	var waitTime time.Duration
+
+	if now.Before(g.blockedUntil) {
+		waitTime = g.blockedUntil.Sub(now)
+	}
+
+	for _, bucket := range g.buckets {
+		// トークンをリセット（時間窓が過ぎた場合）
+		if !now.Before(bucket.reset) {
+			bucket.count = 0
+			bucket.reset = now.Add(bucket.window)
+		}
+
+		if bucket.count >= bucket.limit {
+			if wait := bucket.reset.Sub(now); wait > waitTime {
+				waitTime = wait
+			}
+		}
+	}
+
+	// 待機が必要だが残り時間が極小の場合は少し待つ
+	if waitTime > 0 && waitTime < 10*time.Millisecond {
+		waitTime = 10 * time.Millisecond
+	}
+
+	return waitTime
+}
+
+func (g *rateLimitGroup) consume() {
+	for _, bucket := range g.buckets {
+		bucket.count++
+	}
+}
+
+func (g *rateLimitGroup) block(until time.Time) {
+	if until.After(g.blockedUntil) {
+		g.blockedUntil = until
+	}
+}
+
+// ヘッダーの制限値に合わせて時間窓を作り直す（既存の窓の使用数は維持）
+func (g *rateLimitGroup) resize(limits, counts map[time.Duration]int, now time.Time) {
+	existing := make(map[time.Duration]*rateBucket, len(g.buckets))
+	for _, bucket := range g.buckets {
+		existing[bucket.window] = bucket
+	}
+
+	buckets := make([]*rateBucket, 0, len(limits))
+	for window, limit := range limits {
+		bucket, ok := existing[window]
+		if !ok {
+			bucket = &rateBucket{window: window, reset: now.Add(window)}
+		}
+		bucket.limit = limit
+
+		// 送信済みで未応答のリクエストもあるため、サーバー側の使用数とローカルの大きい方を採用
+		if count, ok := counts[window]; ok && count > bucket.count {
+			bucket.count = count
+		}
+
+		buckets = append(buckets, bucket)
+	}
+
+	slices.SortFunc(buckets, func(a, b *rateBucket) int {
+		return int(a.window - b.window)
+	})
+	g.buckets = buckets
+}
+
+func (g *rateLimitGroup) status(routing, scope string, now time.Time) []RateLimitStatus {
+	var status []RateLimitStatus
+	for _, bucket := range g.buckets {
+		// 期限切れチェック
+		if !now.Before(bucket.reset) {
+			bucket.count = 0
+			bucket.reset = now.Add(bucket.window)
+		}
+
+		status = append(status, RateLimitStatus{
+			Routing:   routing,
+			Scope:     scope,
+			Limit:     bucket.limit,
+			Window:    bucket.window,
+			Available: max(bucket.limit-bucket.count, 0),
+			Reset:     bucket.reset,
+		})
+	}
+	return status
+}
+
+// "20:1,100:120" 形式（回数:秒）のヘッダーを時間窓ごとの値に変換
+func parseRateLimitHeader(value string) map[time.Duration]int {
+	result := make(map[time.Duration]int)
+	if value == "" {
+		return result
+	}
+
+	for _, part := range strings.Split(value, ",") {
+		countStr, secondsStr, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			continue
+		}
+
+		count, err := strconv.Atoi(countStr)
+		if err != nil {
+			continue
+		}
+		seconds, err := strconv.Atoi(secondsStr)
+		if err != nil || seconds <= 0 {
+			continue
+		}
+
+		result[time.Duration(seconds)*time.Second] = count
+	}
+
+	return result
 }
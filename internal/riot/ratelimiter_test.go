@@ -0,0 +1,245 @@
+package riot
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimitHeader(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  map[time.Duration]int
+	}{
+		{name: "空", value: "", want: map[time.Duration]int{}},
+		{
+			name:  "開発者キー",
+			value: "20:1,100:120",
+			want:  map[time.Duration]int{time.Second: 20, 2 * time.Minute: 100},
+		},
+		{
+			name:  "空白を含む",
+			value: " 500:10 , 30000:600 ",
+			want:  map[time.Duration]int{10 * time.Second: 500, 10 * time.Minute: 30000},
+		},
+		{
+			name:  "使用数",
+			value: "0:1,57:120",
+			want:  map[time.Duration]int{time.Second: 0, 2 * time.Minute: 57},
+		},
+		{
+			name:  "不正な要素は無視",
+			value: "abc,20:1,x:10,5:y,3:0,7:-1,100",
+			want:  map[time.Duration]int{time.Second: 20},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRateLimitHeader(tt.value); !maps.Equal(got, tt.want) {
+				t.Errorf("parseRateLimitHeader(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimiterUpdate(t *testing.T) {
+	type bucket struct {
+		scope     string
+		limit     int
+		window    time.Duration
+		available int
+	}
+
+	tests := []struct {
+		name     string
+		requests int // Update の前に Wait で消費する回数
+		method   string
+		header   http.Header
+		want     []bucket
+	}{
+		{
+			name:     "ヘッダーなしは開発者キーの制限",
+			requests: 3,
+			method:   MethodMatchDetail,
+			header:   http.Header{},
+			want: []bucket{
+				{"app", 20, time.Second, 17},
+				{"app", 100, 2 * time.Minute, 97},
+			},
+		},
+		{
+			// 新しい時間窓の使用数はサーバー側の値から始める
+			name:     "本番キーの制限に置き換え",
+			requests: 3,
+			method:   MethodMatchDetail,
+			header: http.Header{
+				"X-App-Rate-Limit":       {"500:10,30000:600"},
+				"X-App-Rate-Limit-Count": {"3:10,3:600"},
+			},
+			want: []bucket{
+				{"app", 500, 10 * time.Second, 497},
+				{"app", 30000, 10 * time.Minute, 29997},
+			},
+		},
+		{
+			name:     "同じ時間窓は使用数を維持して上限のみ変更",
+			requests: 3,
+			method:   MethodMatchDetail,
+			header: http.Header{
+				"X-App-Rate-Limit": {"50:1,300:120"},
+			},
+			want: []bucket{
+				{"app", 50, time.Second, 47},
+				{"app", 300, 2 * time.Minute, 297},
+			},
+		},
+		{
+			name:     "サーバー側の使用数が多い場合はそちらを採用",
+			requests: 1,
+			method:   MethodMatchDetail,
+			header: http.Header{
+				"X-App-Rate-Limit":       {"20:1,100:120"},
+				"X-App-Rate-Limit-Count": {"5:1,90:120"},
+			},
+			want: []bucket{
+				{"app", 20, time.Second, 15},
+				{"app", 100, 2 * time.Minute, 10},
+			},
+		},
+		{
+			name:     "メソッドの制限はアプリと別に管理",
+			requests: 2,
+			method:   MethodMatchIDs,
+			header: http.Header{
+				"X-Method-Rate-Limit":       {"2000:10"},
+				"X-Method-Rate-Limit-Count": {"2:10"},
+			},
+			want: []bucket{
+				{"app", 20, time.Second, 18},
+				{"app", 100, 2 * time.Minute, 98},
+				{MethodMatchIDs, 2000, 10 * time.Second, 1998},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rl := NewRateLimiter()
+			for range tt.requests {
+				if err := rl.Wait(context.Background(), RegionAsia, tt.method); err != nil {
+					t.Fatalf("Wait: %v", err)
+				}
+			}
+
+			rl.Update(RegionAsia, tt.method, tt.header)
+
+			status := rl.GetStatus()
+			if len(status) != len(tt.want) {
+				t.Fatalf("GetStatus = %+v, want %d buckets", status, len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := bucket{status[i].Scope, status[i].Limit, status[i].Window, status[i].Available}
+				if got != want {
+					t.Errorf("bucket %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+// 縮小後の制限を超えた場合は次の時間窓まで待機する
+func TestRateLimiterWaitAfterResize(t *testing.T) {
+	rl := NewRateLimiter()
+	rl.Update(RegionAsia, MethodMatchDetail, http.Header{"X-Method-Rate-Limit": {"2:1"}})
+
+	ctx := context.Background()
+	for range 2 {
+		if err := rl.Wait(ctx, RegionAsia, MethodMatchDetail); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+
+	// 他のメソッドは制限されない
+	start := time.Now()
+	if err := rl.Wait(ctx, RegionAsia, MethodMatchIDs); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("別のメソッドが %v 待機した", elapsed)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if err := rl.Wait(waitCtx, RegionAsia, MethodMatchDetail); err == nil {
+		t.Error("メソッドの制限を超えても待機しなかった")
+	}
+}
+
+func TestRateLimiterBudget(t *testing.T) {
+	tests := []struct {
+		name     string
+		requests int // Budget の前に Wait で消費する回数
+		method   string
+		header   http.Header
+		want     int
+	}{
+		{name: "未使用は開発者キーの長い時間窓", method: MethodMatchDetail, want: 100},
+		{name: "使用数を差し引く", requests: 3, method: MethodMatchDetail, want: 97},
+		{
+			name:     "本番キー",
+			requests: 1,
+			method:   MethodMatchDetail,
+			header: http.Header{
+				"X-App-Rate-Limit":       {"500:10,30000:600"},
+				"X-App-Rate-Limit-Count": {"1:10,1:600"},
+			},
+			want: 29999,
+		},
+		{
+			name:     "メソッドの制限の方が小さい",
+			requests: 2,
+			method:   MethodMatchDetail,
+			header: http.Header{
+				"X-Method-Rate-Limit":       {"2000:10,5:600"},
+				"X-Method-Rate-Limit-Count": {"2:10,2:600"},
+			},
+			want: 3,
+		},
+		{
+			name:     "使い切った場合は0",
+			requests: 2,
+			method:   MethodMatchIDs,
+			header: http.Header{
+				"X-App-Rate-Limit":       {"2:120"},
+				"X-App-Rate-Limit-Count": {"2:120"},
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rl := NewRateLimiter()
+			for range tt.requests {
+				if err := rl.Wait(context.Background(), RegionAsia, tt.method); err != nil {
+					t.Fatalf("Wait: %v", err)
+				}
+			}
+			if tt.header != nil {
+				rl.Update(RegionAsia, tt.method, tt.header)
+			}
+
+			if got := rl.Budget(RegionAsia, tt.method); got != tt.want {
+				t.Errorf("Budget = %d, want %d", got, tt.want)
+			}
+			// 他のルーティング値は影響を受けない
+			if got := rl.Budget(RegionAmericas, tt.method); got != 100 {
+				t.Errorf("Budget(americas) = %d, want 100", got)
+			}
+		})
+	}
+}
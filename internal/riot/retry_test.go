@@ -0,0 +1,203 @@
+package riot_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+	"github.com/MicronGit/Summoner-Analysis/internal/riot/riottest"
+)
+
+// テスト用の短い待機時間
+func testRetryPolicy(maxAttempts int) riot.RetryPolicy {
+	return riot.RetryPolicy{
+		MaxAttempts:       maxAttempts,
+		NetworkErrorDelay: time.Millisecond,
+		ServerErrorDelay:  time.Millisecond,
+		RateLimitDelay:    time.Millisecond,
+	}
+}
+
+// 最初の failures 回だけ 503 を返し、以降はフェイクサーバーに渡すハンドラー
+type failingHandler struct {
+	next     http.Handler
+	mu       sync.Mutex
+	failures int
+}
+
+func (h *failingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	fail := h.failures > 0
+	if fail {
+		h.failures--
+	}
+	h.mu.Unlock()
+
+	if fail {
+		http.Error(w, `{"status":{"message":"Service unavailable","status_code":503}}`, http.StatusServiceUnavailable)
+		return
+	}
+	h.next.ServeHTTP(w, r)
+}
+
+func TestServerErrorRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int
+		maxAttempts  int
+		wantRequests int // フェイクサーバーまで届いたリクエスト数
+		wantErr      error
+	}{
+		{name: "エラーなし", failures: 0, maxAttempts: 3, wantRequests: 1},
+		{name: "再試行で成功", failures: 2, maxAttempts: 3, wantRequests: 1},
+		{name: "試行回数を超過", failures: 3, maxAttempts: 3, wantRequests: 0, wantErr: riot.ErrServer},
+		{name: "再試行なし", failures: 1, maxAttempts: 1, wantRequests: 0, wantErr: riot.ErrServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, err := riottest.NewServer(riottest.Config{})
+			if err != nil {
+				t.Fatalf("NewServer: %v", err)
+			}
+			fake.AddMatch(testMatch(1))
+
+			handler := &failingHandler{next: fake, failures: tt.failures}
+			ts := httptest.NewServer(handler)
+			t.Cleanup(ts.Close)
+
+			client := riot.NewClient("test-key", riot.RegionAsia,
+				riot.WithBaseURL(ts.URL),
+				riot.WithRetryPolicy(testRetryPolicy(tt.maxAttempts)),
+			)
+
+			detail, err := client.GetMatchDetailWithContext(context.Background(), testMatchID(1))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("GetMatchDetailWithContext: %v", err)
+			} else if detail.Metadata.MatchID != testMatchID(1) {
+				t.Errorf("MatchID = %s, want %s", detail.Metadata.MatchID, testMatchID(1))
+			}
+
+			if got := fake.Requests(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestRateLimitRetryAfter(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  riottest.Config
+	}{
+		{name: "アプリの制限", cfg: riottest.Config{AppRateLimit: "1:1"}},
+		{name: "メソッドの制限", cfg: riottest.Config{MethodRateLimit: "1:1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, fake := newTestClient(t, tt.cfg, 1)
+			ctx := context.Background()
+
+			if _, err := first.GetMatchDetailWithContext(ctx, testMatchID(1)); err != nil {
+				t.Fatalf("GetMatchDetailWithContext: %v", err)
+			}
+
+			// 制限を知らない別のクライアントは 429 を受け、Retry-After だけ待って再試行する
+			second := riot.NewClient("test-key", riot.RegionAsia,
+				riot.WithBaseURL(first.BaseURL),
+				riot.WithRetryPolicy(testRetryPolicy(2)),
+			)
+
+			start := time.Now()
+			if _, err := second.GetMatchDetailWithContext(ctx, testMatchID(1)); err != nil {
+				t.Fatalf("再試行後も失敗: %v", err)
+			}
+			if elapsed := time.Since(start); elapsed < time.Second {
+				t.Errorf("Retry-After を待たずに再試行した (%v)", elapsed)
+			}
+			if got := fake.Requests(); got != 3 {
+				t.Errorf("requests = %d, want 3", got)
+			}
+		})
+	}
+}
+
+func TestRateLimitExhausted(t *testing.T) {
+	// 基盤側の 429 は Retry-After: 0 で即座に再試行し、試行回数を超えたらエラー
+	client, fake := newTestClient(t, riottest.Config{RateLimitErrorRate: 1}, 1)
+	client.Retry = testRetryPolicy(3)
+
+	_, err := client.GetMatchDetailWithContext(context.Background(), testMatchID(1))
+	if !errors.Is(err, riot.ErrRateLimited) {
+		t.Fatalf("err = %v, want %v", err, riot.ErrRateLimited)
+	}
+
+	var apiErr *riot.RiotAPIError
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter != 0 {
+		t.Errorf("RiotAPIError = %+v, want RetryAfter 0", apiErr)
+	}
+	if got := fake.Requests(); got != 3 {
+		t.Errorf("requests = %d, want 3", got)
+	}
+}
+
+func TestRateLimitResizeFromServer(t *testing.T) {
+	type bucket struct {
+		scope     string
+		limit     int
+		window    time.Duration
+		available int
+	}
+
+	client, fake := newTestClient(t, riottest.Config{
+		AppRateLimit:    "50:10,1000:600",
+		MethodRateLimit: "2:10",
+	}, 1)
+	ctx := context.Background()
+
+	for range 2 {
+		if _, err := client.GetMatchDetailWithContext(ctx, testMatchID(1)); err != nil {
+			t.Fatalf("GetMatchDetailWithContext: %v", err)
+		}
+	}
+
+	// 開発者キーの既定値がサーバーの制限に置き換わる
+	want := []bucket{
+		{"app", 50, 10 * time.Second, 48},
+		{"app", 1000, 10 * time.Minute, 998},
+		{riot.MethodMatchDetail, 2, 10 * time.Second, 0},
+	}
+	status := client.RateLimiter.GetStatus()
+	if len(status) != len(want) {
+		t.Fatalf("GetStatus = %+v, want %d buckets", status, len(want))
+	}
+	for i, w := range want {
+		if status[i].Routing != riot.RegionAsia {
+			t.Errorf("bucket %d routing = %s, want %s", i, status[i].Routing, riot.RegionAsia)
+		}
+		got := bucket{status[i].Scope, status[i].Limit, status[i].Window, status[i].Available}
+		if got != w {
+			t.Errorf("bucket %d = %+v, want %+v", i, got, w)
+		}
+	}
+
+	// 縮小後の制限はクライアント側で待機し、サーバーには送らない
+	waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetMatchDetailWithContext(waitCtx, testMatchID(1)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if got := fake.Requests(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
@@ -0,0 +1,89 @@
+package riottest
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 固定時間窓のカウンタ
+type limitCounter struct {
+	limit  int
+	window time.Duration
+	count  int
+	reset  time.Time
+}
+
+// "20:1,100:120" 形式（回数:秒）の制限を解析
+func parseLimits(value string) []*limitCounter {
+	var limits []*limitCounter
+
+	for _, part := range strings.Split(value, ",") {
+		countStr, secondsStr, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			continue
+		}
+
+		limit, err := strconv.Atoi(countStr)
+		if err != nil {
+			continue
+		}
+		seconds, err := strconv.Atoi(secondsStr)
+		if err != nil || seconds <= 0 {
+			continue
+		}
+
+		limits = append(limits, &limitCounter{
+			limit:  limit,
+			window: time.Duration(seconds) * time.Second,
+		})
+	}
+
+	return limits
+}
+
+// 超過している場合は次の時間窓までの待機時間を返す
+func waitTime(limits []*limitCounter, now time.Time) time.Duration {
+	var wait time.Duration
+	for _, counter := range limits {
+		if !now.Before(counter.reset) {
+			counter.count = 0
+			counter.reset = now.Add(counter.window)
+		}
+		if counter.count >= counter.limit {
+			wait = max(wait, counter.reset.Sub(now))
+		}
+	}
+	return wait
+}
+
+func consume(limits []*limitCounter) {
+	for _, counter := range limits {
+		counter.count++
+	}
+}
+
+// X-*-Rate-Limit / X-*-Rate-Limit-Count ヘッダーを設定
+func setLimitHeaders(header http.Header, name string, limits []*limitCounter) {
+	if len(limits) == 0 {
+		return
+	}
+
+	var limitParts, countParts []string
+	for _, counter := range limits {
+		seconds := int(counter.window.Seconds())
+		limitParts = append(limitParts, fmt.Sprintf("%d:%d", counter.limit, seconds))
+		countParts = append(countParts, fmt.Sprintf("%d:%d", counter.count, seconds))
+	}
+
+	header.Set(name, strings.Join(limitParts, ","))
+	header.Set(name+"-Count", strings.Join(countParts, ","))
+}
+
+// Retry-After は秒単位の整数（切り上げ）
+func retryAfterSeconds(wait time.Duration) int {
+	return int(math.Ceil(wait.Seconds()))
+}
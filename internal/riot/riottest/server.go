@@ -0,0 +1,550 @@
+// Package riottest は Riot API のフェイクサーバーを提供する
+// フィクスチャのJSONを account-v1 / match-v5 と同じパスで返し、
+// 429・5xx・遅延・レート制限ヘッダーを再現してリトライ処理などをオフラインで確認できる
+package riottest
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+// フェイクサーバーの設定
+type Config struct {
+	// フィクスチャのディレクトリ
+	//   accounts/*.json  account-v1 の Account
+	//   matches/*.json   match-v5 の MatchDetail
+	//   timelines/*.json match-v5 の MatchTimeline
+	//   league/*.json    league-v4 の LeagueEntry の配列（1ファイル1プレイヤー）
+	//   mastery/*.json   champion-mastery-v4 の ChampionMastery の配列（1ファイル1プレイヤー）
+	//   spectator/*.json spectator-v5 の ActiveGame（参加者全員のPUUIDで参照できる）
+	FixtureDir string
+
+	APIKey string // 指定した場合は X-Riot-Token を検証
+
+	Latency            time.Duration // レスポンスまでの遅延
+	RateLimitErrorRate float64       // ランダムに 429 を返す確率（0-1）
+	ServerErrorRate    float64       // ランダムに 5xx を返す確率（0-1）
+	RetryAfter         time.Duration // ランダムな 429 に付与する Retry-After
+
+	AppRateLimit    string // アプリ全体の制限（例: "20:1,100:120"、空で制限なし）
+	MethodRateLimit string // メソッドごとの制限（同形式）
+}
+
+// Riot API のフェイクサーバー（http.Handler）
+type Server struct {
+	cfg Config
+	mux *http.ServeMux
+
+	mu              sync.Mutex
+	accounts        map[string]riot.Account // 小文字の "gameName#tagLine" -> Account
+	accountsByPUUID map[string]riot.Account
+	matches         map[string]*riot.MatchDetail
+	timelines       map[string]*riot.MatchTimeline
+	leagueEntries   map[string][]riot.LeagueEntry // PUUID -> エントリー
+	masteries       map[string][]riot.ChampionMastery
+	activeGames     map[string]*riot.ActiveGame // 参加者のPUUID -> 試合
+	appLimits       []*limitCounter
+	methodLimits    map[string][]*limitCounter
+	requests        int
+}
+
+func NewServer(cfg Config) (*Server, error) {
+	s := &Server{
+		cfg:             cfg,
+		mux:             http.NewServeMux(),
+		accounts:        make(map[string]riot.Account),
+		accountsByPUUID: make(map[string]riot.Account),
+		matches:         make(map[string]*riot.MatchDetail),
+		timelines:       make(map[string]*riot.MatchTimeline),
+		leagueEntries:   make(map[string][]riot.LeagueEntry),
+		masteries:       make(map[string][]riot.ChampionMastery),
+		activeGames:     make(map[string]*riot.ActiveGame),
+		appLimits:       parseLimits(cfg.AppRateLimit),
+		methodLimits:    make(map[string][]*limitCounter),
+	}
+
+	if cfg.FixtureDir != "" {
+		if err := s.loadFixtures(cfg.FixtureDir); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mux.HandleFunc("GET /riot/account/v1/accounts/by-riot-id/{gameName}/{tagLine}",
+		s.handle(riot.MethodAccountByRiotID, s.handleAccountByRiotID))
+	s.mux.HandleFunc("GET /riot/account/v1/accounts/by-puuid/{puuid}",
+		s.handle("account-v1.by-puuid", s.handleAccountByPUUID))
+	s.mux.HandleFunc("GET /lol/match/v5/matches/by-puuid/{puuid}/ids",
+		s.handle(riot.MethodMatchIDs, s.handleMatchIDs))
+	s.mux.HandleFunc("GET /lol/match/v5/matches/{matchId}",
+		s.handle(riot.MethodMatchDetail, s.handleMatch))
+	s.mux.HandleFunc("GET /lol/match/v5/matches/{matchId}/timeline",
+		s.handle(riot.MethodMatchTimeline, s.handleTimeline))
+	s.mux.HandleFunc("GET /lol/league/v4/entries/by-puuid/{puuid}",
+		s.handle(riot.MethodLeagueEntriesByPUUID, s.handleLeagueEntries))
+	s.mux.HandleFunc("GET /lol/champion-mastery/v4/champion-masteries/by-puuid/{puuid}",
+		s.handle(riot.MethodChampionMasteries, s.handleMasteries))
+	s.mux.HandleFunc("GET /lol/spectator/v5/active-games/by-summoner/{puuid}",
+		s.handle(riot.MethodActiveGame, s.handleActiveGame))
+
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// アカウントを登録
+func (s *Server) AddAccount(account riot.Account) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accounts[accountKey(account.SummonerName, account.TagLine)] = account
+	s.accountsByPUUID[account.PUUID] = account
+}
+
+// マッチ詳細を登録
+func (s *Server) AddMatch(match riot.MatchDetail) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.matches[match.Metadata.MatchID] = &match
+}
+
+// タイムラインを登録
+func (s *Server) AddTimeline(timeline riot.MatchTimeline) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.timelines[timeline.Metadata.MatchID] = &timeline
+}
+
+// リーグエントリーを登録（PUUID ごとに追加）
+func (s *Server) AddLeagueEntry(entry riot.LeagueEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.leagueEntries[entry.PUUID] = append(s.leagueEntries[entry.PUUID], entry)
+}
+
+// チャンピオンマスタリーを登録（PUUID ごとに追加）
+func (s *Server) AddChampionMastery(mastery riot.ChampionMastery) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.masteries[mastery.PUUID] = append(s.masteries[mastery.PUUID], mastery)
+}
+
+// 進行中の試合を登録（参加者全員が試合中になる）
+func (s *Server) AddActiveGame(game riot.ActiveGame) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, participant := range game.Participants {
+		if participant.PUUID != "" {
+			s.activeGames[participant.PUUID] = &game
+		}
+	}
+}
+
+// 受け付けたリクエスト数
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests
+}
+
+func (s *Server) loadFixtures(dir string) error {
+	var accounts []riot.Account
+	if err := loadJSONFiles(filepath.Join(dir, "accounts"), &accounts); err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		s.AddAccount(account)
+	}
+
+	var matches []riot.MatchDetail
+	if err := loadJSONFiles(filepath.Join(dir, "matches"), &matches); err != nil {
+		return err
+	}
+	for _, match := range matches {
+		s.AddMatch(match)
+	}
+
+	var timelines []riot.MatchTimeline
+	if err := loadJSONFiles(filepath.Join(dir, "timelines"), &timelines); err != nil {
+		return err
+	}
+	for _, timeline := range timelines {
+		s.AddTimeline(timeline)
+	}
+
+	var leagues [][]riot.LeagueEntry
+	if err := loadJSONFiles(filepath.Join(dir, "league"), &leagues); err != nil {
+		return err
+	}
+	for _, entries := range leagues {
+		for _, entry := range entries {
+			s.AddLeagueEntry(entry)
+		}
+	}
+
+	var masteries [][]riot.ChampionMastery
+	if err := loadJSONFiles(filepath.Join(dir, "mastery"), &masteries); err != nil {
+		return err
+	}
+	for _, champions := range masteries {
+		for _, mastery := range champions {
+			s.AddChampionMastery(mastery)
+		}
+	}
+
+	var games []riot.ActiveGame
+	if err := loadJSONFiles(filepath.Join(dir, "spectator"), &games); err != nil {
+		return err
+	}
+	for _, game := range games {
+		s.AddActiveGame(game)
+	}
+
+	return nil
+}
+
+// ディレクトリ内の *.json を読み込む（ディレクトリがない場合は何もしない）
+func loadJSONFiles[T any](dir string, out *[]T) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("フィクスチャ検索エラー: %w", err)
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("フィクスチャ読み込みエラー (%s): %w", file, err)
+		}
+
+		var v T
+		if err := json.Unmarshal(data, &v); err != nil {
+			return fmt.Errorf("フィクスチャJSON解析エラー (%s): %w", file, err)
+		}
+		*out = append(*out, v)
+	}
+
+	return nil
+}
+
+// 認証・遅延・レート制限・エラー注入を共通で処理
+func (s *Server) handle(method string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("X-Riot-Token")
+		if token == "" {
+			writeError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		if s.cfg.APIKey != "" && token != s.cfg.APIKey {
+			writeError(w, http.StatusForbidden, "Forbidden")
+			return
+		}
+
+		if s.cfg.Latency > 0 {
+			select {
+			case <-time.After(s.cfg.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if !s.checkRateLimit(w, method) {
+			return
+		}
+
+		if s.cfg.RateLimitErrorRate > 0 && rand.Float64() < s.cfg.RateLimitErrorRate {
+			w.Header().Set("Retry-After", strconv.Itoa(int(s.cfg.RetryAfter.Seconds())))
+			w.Header().Set("X-Rate-Limit-Type", "service")
+			writeError(w, http.StatusTooManyRequests, "Rate limit exceeded")
+			return
+		}
+
+		if s.cfg.ServerErrorRate > 0 && rand.Float64() < s.cfg.ServerErrorRate {
+			writeError(w, http.StatusServiceUnavailable, "Service unavailable")
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// レート制限を適用し、ヘッダーを付与（超過時は 429 を返して false）
+func (s *Server) checkRateLimit(w http.ResponseWriter, method string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	now := time.Now()
+
+	methodLimits, ok := s.methodLimits[method]
+	if !ok {
+		methodLimits = parseLimits(s.cfg.MethodRateLimit)
+		s.methodLimits[method] = methodLimits
+	}
+
+	appWait := waitTime(s.appLimits, now)
+	methodWait := waitTime(methodLimits, now)
+
+	if appWait == 0 && methodWait == 0 {
+		consume(s.appLimits)
+		consume(methodLimits)
+	}
+
+	setLimitHeaders(w.Header(), "X-App-Rate-Limit", s.appLimits)
+	setLimitHeaders(w.Header(), "X-Method-Rate-Limit", methodLimits)
+
+	switch {
+	case appWait > 0:
+		w.Header().Set("X-Rate-Limit-Type", "application")
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(appWait)))
+	case methodWait > 0:
+		w.Header().Set("X-Rate-Limit-Type", "method")
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(methodWait)))
+	default:
+		return true
+	}
+
+	writeError(w, http.StatusTooManyRequests, "Rate limit exceeded")
+	return false
+}
+
+func (s *Server) handleAccountByRiotID(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	account, ok := s.accounts[accountKey(r.PathValue("gameName"), r.PathValue("tagLine"))]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "Data not found - No results found for player with riot id")
+		return
+	}
+	writeJSON(w, account)
+}
+
+func (s *Server) handleAccountByPUUID(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	account, ok := s.accountsByPUUID[r.PathValue("puuid")]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "Data not found - No results found for player with puuid")
+		return
+	}
+	writeJSON(w, account)
+}
+
+func (s *Server) handleMatchIDs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	start, err := intParam(query.Get("start"), 0)
+	if err != nil || start < 0 {
+		writeError(w, http.StatusBadRequest, "Bad request - invalid start")
+		return
+	}
+	count, err := intParam(query.Get("count"), 20)
+	if err != nil || count < 0 || count > 100 {
+		writeError(w, http.StatusBadRequest, "Bad request - count must be between 0 and 100")
+		return
+	}
+	queue, err := intParam(query.Get("queue"), 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad request - invalid queue")
+		return
+	}
+	startTime, err := intParam(query.Get("startTime"), 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad request - invalid startTime")
+		return
+	}
+	endTime, err := intParam(query.Get("endTime"), 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad request - invalid endTime")
+		return
+	}
+	matchType := query.Get("type")
+	puuid := r.PathValue("puuid")
+
+	s.mu.Lock()
+	var matches []*riot.MatchDetail
+	for _, match := range s.matches {
+		if !hasParticipant(match, puuid) {
+			continue
+		}
+		if queue != 0 && match.Info.QueueID != queue {
+			continue
+		}
+		if !matchesType(match.Info.QueueID, matchType) {
+			continue
+		}
+
+		startedAt := gameStartSeconds(match)
+		if startTime != 0 && startedAt < int64(startTime) {
+			continue
+		}
+		if endTime != 0 && startedAt > int64(endTime) {
+			continue
+		}
+
+		matches = append(matches, match)
+	}
+	s.mu.Unlock()
+
+	// 新しい順
+	slices.SortFunc(matches, func(a, b *riot.MatchDetail) int {
+		if c := cmp.Compare(gameStartSeconds(b), gameStartSeconds(a)); c != 0 {
+			return c
+		}
+		return strings.Compare(b.Metadata.MatchID, a.Metadata.MatchID)
+	})
+
+	ids := []string{}
+	for i := start; i < len(matches) && len(ids) < count; i++ {
+		ids = append(ids, matches[i].Metadata.MatchID)
+	}
+
+	writeJSON(w, ids)
+}
+
+func (s *Server) handleMatch(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	match, ok := s.matches[r.PathValue("matchId")]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "Data not found - match file not found")
+		return
+	}
+	writeJSON(w, match)
+}
+
+func (s *Server) handleTimeline(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	timeline, ok := s.timelines[r.PathValue("matchId")]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "Data not found - match file not found")
+		return
+	}
+	writeJSON(w, timeline)
+}
+
+// 未ランクのプレイヤーは空配列を返す（実際の API と同じ）
+func (s *Server) handleLeagueEntries(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	entries := s.leagueEntries[r.PathValue("puuid")]
+	s.mu.Unlock()
+
+	if entries == nil {
+		entries = []riot.LeagueEntry{}
+	}
+	writeJSON(w, entries)
+}
+
+// ポイントの多い順に返す
+func (s *Server) handleMasteries(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	masteries := slices.Clone(s.masteries[r.PathValue("puuid")])
+	s.mu.Unlock()
+
+	slices.SortFunc(masteries, func(a, b riot.ChampionMastery) int {
+		return b.ChampionPoints - a.ChampionPoints
+	})
+	if masteries == nil {
+		masteries = []riot.ChampionMastery{}
+	}
+	writeJSON(w, masteries)
+}
+
+// 試合中でないプレイヤーは 404（実際の API と同じ）
+func (s *Server) handleActiveGame(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	game, ok := s.activeGames[r.PathValue("puuid")]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "Data not found - spectator game info isn't found")
+		return
+	}
+	writeJSON(w, game)
+}
+
+func accountKey(gameName, tagLine string) string {
+	return strings.ToLower(gameName) + "#" + strings.ToLower(tagLine)
+}
+
+func hasParticipant(match *riot.MatchDetail, puuid string) bool {
+	if slices.Contains(match.Metadata.Participants, puuid) {
+		return true
+	}
+	for _, participant := range match.Info.Participants {
+		if participant.PUUID == puuid {
+			return true
+		}
+	}
+	return false
+}
+
+// match-v5 の type パラメータに対応するキューか判定
+func matchesType(queueID int, matchType string) bool {
+	switch matchType {
+	case "":
+		return true
+	case "ranked":
+		return riot.IsRankedQueue(queueID)
+	case "normal":
+		// 実際の API と同じく ARAM・アリーナなどランク戦以外のマッチングのキューをすべて含む（カスタムゲームは除く）
+		return queueID != 0 && !riot.IsRankedQueue(queueID)
+	default:
+		return false
+	}
+}
+
+func gameStartSeconds(match *riot.MatchDetail) int64 {
+	if match.Info.GameStartTime != 0 {
+		return match.Info.GameStartTime / 1000
+	}
+	return match.Info.GameCreation / 1000
+}
+
+func intParam(value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Riot API と同じ形式のエラーレスポンス
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]any{
+		"status": map[string]any{
+			"message":     message,
+			"status_code": statusCode,
+		},
+	})
+}
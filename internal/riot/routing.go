@@ -0,0 +1,163 @@
+package riot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// プラットフォーム（league-v4・summoner-v4・spectator-v5・champion-mastery-v4 の接続先）
+const (
+	PlatformBR1  = "br1"
+	PlatformEUN1 = "eun1"
+	PlatformEUW1 = "euw1"
+	PlatformJP1  = "jp1"
+	PlatformKR   = "kr"
+	PlatformLA1  = "la1"
+	PlatformLA2  = "la2"
+	PlatformME1  = "me1"
+	PlatformNA1  = "na1"
+	PlatformOC1  = "oc1"
+	PlatformPH2  = "ph2"
+	PlatformRU   = "ru"
+	PlatformSG2  = "sg2"
+	PlatformTH2  = "th2"
+	PlatformTR1  = "tr1"
+	PlatformTW2  = "tw2"
+	PlatformVN2  = "vn2"
+)
+
+// リージョン（match-v5・account-v1 の接続先）
+const (
+	RegionAmericas = "americas"
+	RegionAsia     = "asia"
+	RegionEurope   = "europe"
+	RegionSEA      = "sea"
+)
+
+// プラットフォームが属するリージョン（match-v5 の接続先）
+var platformRegions = map[string]string{
+	PlatformNA1:  RegionAmericas,
+	PlatformBR1:  RegionAmericas,
+	PlatformLA1:  RegionAmericas,
+	PlatformLA2:  RegionAmericas,
+	PlatformKR:   RegionAsia,
+	PlatformJP1:  RegionAsia,
+	PlatformEUW1: RegionEurope,
+	PlatformEUN1: RegionEurope,
+	PlatformTR1:  RegionEurope,
+	PlatformRU:   RegionEurope,
+	PlatformME1:  RegionEurope,
+	PlatformOC1:  RegionSEA,
+	PlatformPH2:  RegionSEA,
+	PlatformSG2:  RegionSEA,
+	PlatformTH2:  RegionSEA,
+	PlatformTW2:  RegionSEA,
+	PlatformVN2:  RegionSEA,
+}
+
+// リージョンのみ指定された場合のプラットフォーム
+var defaultPlatforms = map[string]string{
+	RegionAmericas: PlatformNA1,
+	RegionAsia:     PlatformJP1,
+	RegionEurope:   PlatformEUW1,
+	RegionSEA:      PlatformSG2,
+}
+
+// 接続先の組み合わせ
+type Routing struct {
+	Platform string // jp1 / na1 など
+	Region   string // asia / americas / europe / sea
+}
+
+// account-v1 の接続先（account-v1 は sea に対応していないため asia を使用）
+func (r Routing) AccountRegion() string {
+	if r.Region == RegionSEA {
+		return RegionAsia
+	}
+	return r.Region
+}
+
+// リージョン・プラットフォームの指定から接続先を決定
+// platform を指定した場合はそれが優先され、region にはリージョン（asia など）と
+// プラットフォーム（jp1 など）のどちらも指定できる。不明な値はエラー
+func ResolveRouting(region, platform string) (Routing, error) {
+	region = strings.ToLower(strings.TrimSpace(region))
+	platform = strings.ToLower(strings.TrimSpace(platform))
+
+	if platform != "" {
+		platformRegion, ok := platformRegions[platform]
+		if !ok {
+			return Routing{}, fmt.Errorf("%w: プラットフォーム %q", ErrUnknownRouting, platform)
+		}
+		return Routing{Platform: platform, Region: platformRegion}, nil
+	}
+
+	if platformRegion, ok := platformRegions[region]; ok {
+		return Routing{Platform: region, Region: platformRegion}, nil
+	}
+
+	if defaultPlatform, ok := defaultPlatforms[region]; ok {
+		return Routing{Platform: defaultPlatform, Region: region}, nil
+	}
+
+	return Routing{}, fmt.Errorf("%w: リージョン %q", ErrUnknownRouting, region)
+}
+
+// クライアントの接続先
+func (c *Client) Routing() (Routing, error) {
+	return ResolveRouting(c.Region, c.Platform)
+}
+
+// 接続先だけを変えたクライアント（レート制限・キャッシュは共有）
+// 両方とも空の場合や、同じリージョンを指定した場合はそのまま返す
+func (c *Client) ForRouting(region, platform string) (*Client, error) {
+	if region == "" && platform == "" {
+		return c, nil
+	}
+
+	routing, err := ResolveRouting(region, platform)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := c.Routing()
+	if err == nil && current == routing {
+		return c, nil
+	}
+	// リージョンのみの指定で現在と同じリージョンの場合は設定済みのプラットフォームを維持
+	if err == nil && platform == "" && strings.EqualFold(region, current.Region) {
+		return c, nil
+	}
+
+	clone := *c
+	clone.Region = routing.Region
+	clone.Platform = routing.Platform
+	return &clone, nil
+}
+
+// プラットフォーム単位の API のルーティング値
+func (c *Client) platformRouting() (string, error) {
+	routing, err := c.Routing()
+	if err != nil {
+		return "", err
+	}
+	return routing.Platform, nil
+}
+
+// match-v5 のルーティング値
+func (c *Client) regionalRouting() (string, error) {
+	routing, err := c.Routing()
+	if err != nil {
+		return "", err
+	}
+	return routing.Region, nil
+}
+
+// account-v1 のルーティング値
+func (c *Client) accountRouting() (string, error) {
+	routing, err := c.Routing()
+	if err != nil {
+		return "", err
+	}
+	return routing.AccountRegion(), nil
+}
@@ -0,0 +1,137 @@
+package riot
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolveRouting(t *testing.T) {
+	tests := []struct {
+		name             string
+		region, platform string
+		want             Routing
+		wantAccount      string
+	}{
+		// プラットフォームとリージョンの対応表
+		{name: "na1", platform: PlatformNA1, want: Routing{PlatformNA1, RegionAmericas}, wantAccount: RegionAmericas},
+		{name: "br1", platform: PlatformBR1, want: Routing{PlatformBR1, RegionAmericas}, wantAccount: RegionAmericas},
+		{name: "la1", platform: PlatformLA1, want: Routing{PlatformLA1, RegionAmericas}, wantAccount: RegionAmericas},
+		{name: "la2", platform: PlatformLA2, want: Routing{PlatformLA2, RegionAmericas}, wantAccount: RegionAmericas},
+		{name: "kr", platform: PlatformKR, want: Routing{PlatformKR, RegionAsia}, wantAccount: RegionAsia},
+		{name: "jp1", platform: PlatformJP1, want: Routing{PlatformJP1, RegionAsia}, wantAccount: RegionAsia},
+		{name: "euw1", platform: PlatformEUW1, want: Routing{PlatformEUW1, RegionEurope}, wantAccount: RegionEurope},
+		{name: "eun1", platform: PlatformEUN1, want: Routing{PlatformEUN1, RegionEurope}, wantAccount: RegionEurope},
+		{name: "tr1", platform: PlatformTR1, want: Routing{PlatformTR1, RegionEurope}, wantAccount: RegionEurope},
+		{name: "ru", platform: PlatformRU, want: Routing{PlatformRU, RegionEurope}, wantAccount: RegionEurope},
+		{name: "me1", platform: PlatformME1, want: Routing{PlatformME1, RegionEurope}, wantAccount: RegionEurope},
+		// account-v1 は sea に対応していない
+		{name: "oc1", platform: PlatformOC1, want: Routing{PlatformOC1, RegionSEA}, wantAccount: RegionAsia},
+		{name: "ph2", platform: PlatformPH2, want: Routing{PlatformPH2, RegionSEA}, wantAccount: RegionAsia},
+		{name: "sg2", platform: PlatformSG2, want: Routing{PlatformSG2, RegionSEA}, wantAccount: RegionAsia},
+		{name: "th2", platform: PlatformTH2, want: Routing{PlatformTH2, RegionSEA}, wantAccount: RegionAsia},
+		{name: "tw2", platform: PlatformTW2, want: Routing{PlatformTW2, RegionSEA}, wantAccount: RegionAsia},
+		{name: "vn2", platform: PlatformVN2, want: Routing{PlatformVN2, RegionSEA}, wantAccount: RegionAsia},
+
+		// リージョンのみの指定は既定のプラットフォーム
+		{name: "americas", region: RegionAmericas, want: Routing{PlatformNA1, RegionAmericas}, wantAccount: RegionAmericas},
+		{name: "asia", region: RegionAsia, want: Routing{PlatformJP1, RegionAsia}, wantAccount: RegionAsia},
+		{name: "europe", region: RegionEurope, want: Routing{PlatformEUW1, RegionEurope}, wantAccount: RegionEurope},
+		{name: "sea", region: RegionSEA, want: Routing{PlatformSG2, RegionSEA}, wantAccount: RegionAsia},
+
+		{name: "region にプラットフォーム", region: PlatformKR, want: Routing{PlatformKR, RegionAsia}, wantAccount: RegionAsia},
+		{name: "platform を優先", region: RegionEurope, platform: PlatformJP1, want: Routing{PlatformJP1, RegionAsia}, wantAccount: RegionAsia},
+		{name: "大文字・空白", region: " ASIA ", platform: " NA1", want: Routing{PlatformNA1, RegionAmericas}, wantAccount: RegionAmericas},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveRouting(tt.region, tt.platform)
+			if err != nil {
+				t.Fatalf("ResolveRouting(%q, %q): %v", tt.region, tt.platform, err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveRouting(%q, %q) = %+v, want %+v", tt.region, tt.platform, got, tt.want)
+			}
+			if account := got.AccountRegion(); account != tt.wantAccount {
+				t.Errorf("AccountRegion = %s, want %s", account, tt.wantAccount)
+			}
+		})
+	}
+
+	// すべてのプラットフォームが対応表に含まれていること
+	if len(platformRegions) != 17 {
+		t.Errorf("platformRegions に %d 件、テストは17件", len(platformRegions))
+	}
+}
+
+func TestResolveRoutingInvalid(t *testing.T) {
+	tests := []struct {
+		name             string
+		region, platform string
+	}{
+		{name: "未指定"},
+		{name: "空白のみ", region: "  ", platform: " "},
+		{name: "不明なリージョン", region: "oceania"},
+		{name: "不明なプラットフォーム", region: RegionAsia, platform: "jp2"},
+		{name: "platform にリージョン", platform: RegionAsia},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveRouting(tt.region, tt.platform)
+			if !errors.Is(err, ErrUnknownRouting) {
+				t.Errorf("ResolveRouting(%q, %q) = %+v, %v, want %v", tt.region, tt.platform, got, err, ErrUnknownRouting)
+			}
+		})
+	}
+}
+
+func TestForRouting(t *testing.T) {
+	tests := []struct {
+		name             string
+		region, platform string
+		wantSame         bool // 元のクライアントをそのまま返す
+		want             Routing
+		wantErr          bool
+	}{
+		{name: "未指定", wantSame: true, want: Routing{PlatformKR, RegionAsia}},
+		{name: "同じリージョンは設定済みのプラットフォームを維持", region: "ASIA", wantSame: true, want: Routing{PlatformKR, RegionAsia}},
+		{name: "同じプラットフォーム", platform: PlatformKR, wantSame: true, want: Routing{PlatformKR, RegionAsia}},
+		{name: "同じリージョンの別プラットフォーム", platform: PlatformJP1, want: Routing{PlatformJP1, RegionAsia}},
+		{name: "別のリージョン", region: RegionEurope, want: Routing{PlatformEUW1, RegionEurope}},
+		{name: "region にプラットフォーム", region: PlatformNA1, want: Routing{PlatformNA1, RegionAmericas}},
+		{name: "不明な値", region: "mars", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewClient("test-key", RegionAsia, WithPlatform(PlatformKR))
+
+			client, err := base.ForRouting(tt.region, tt.platform)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnknownRouting) {
+					t.Errorf("err = %v, want %v", err, ErrUnknownRouting)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ForRouting(%q, %q): %v", tt.region, tt.platform, err)
+			}
+
+			if same := client == base; same != tt.wantSame {
+				t.Errorf("同じクライアント = %v, want %v", same, tt.wantSame)
+			}
+			if got, _ := client.Routing(); got != tt.want {
+				t.Errorf("Routing = %+v, want %+v", got, tt.want)
+			}
+
+			// レート制限は共有し、元のクライアントの接続先は変えない
+			if client.RateLimiter != base.RateLimiter {
+				t.Error("RateLimiter が共有されていない")
+			}
+			if got, _ := base.Routing(); got != (Routing{PlatformKR, RegionAsia}) {
+				t.Errorf("元のクライアントの Routing = %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package riot
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// 進行中の試合（spectator-v5）
+type ActiveGame struct {
+	GameID            int64                   `json:"gameId"`
+	GameType          string                  `json:"gameType"`
+	GameMode          string                  `json:"gameMode"`
+	GameStartTime     int64                   `json:"gameStartTime"` // ミリ秒（ロード中は0）
+	GameLength        int64                   `json:"gameLength"`    // 秒
+	GameQueueConfigID int                     `json:"gameQueueConfigId"`
+	MapID             int                     `json:"mapId"`
+	PlatformID        string                  `json:"platformId"`
+	BannedChampions   []BannedChampion        `json:"bannedChampions"`
+	Participants      []ActiveGameParticipant `json:"participants"`
+	Observers         Observer                `json:"observers"`
+}
+
+// 試合開始時刻（ロード中の場合はゼロ値）
+func (g *ActiveGame) StartTime() time.Time {
+	if g.GameStartTime == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(g.GameStartTime)
+}
+
+type BannedChampion struct {
+	ChampionID int `json:"championId"`
+	PickTurn   int `json:"pickTurn"`
+	TeamID     int `json:"teamId"`
+}
+
+type ActiveGameParticipant struct {
+	PUUID                    string                    `json:"puuid"` // ボットの場合は空
+	RiotID                   string                    `json:"riotId"`
+	ChampionID               int                       `json:"championId"`
+	TeamID                   int                       `json:"teamId"`
+	Bot                      bool                      `json:"bot"`
+	ProfileIconID            int                       `json:"profileIconId"`
+	Spell1ID                 int                       `json:"spell1Id"`
+	Spell2ID                 int                       `json:"spell2Id"`
+	Perks                    Perks                     `json:"perks"`
+	GameCustomizationObjects []GameCustomizationObject `json:"gameCustomizationObjects"`
+}
+
+// ルーン構成
+type Perks struct {
+	PerkIDs      []int `json:"perkIds"`
+	PerkStyle    int   `json:"perkStyle"`
+	PerkSubStyle int   `json:"perkSubStyle"`
+}
+
+type GameCustomizationObject struct {
+	Category string `json:"category"`
+	Content  string `json:"content"`
+}
+
+type Observer struct {
+	EncryptionKey string `json:"encryptionKey"`
+}
+
+// プレイヤーが参加中の試合を取得（試合中でない場合は ErrNotFound）
+func (c *Client) GetActiveGameWithContext(ctx context.Context, puuid string) (*ActiveGame, error) {
+	routing, err := c.platformRouting()
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/lol/spectator/v5/active-games/by-summoner/%s", url.PathEscape(puuid))
+
+	var game ActiveGame
+	if err := c.getJSON(ctx, routing, MethodActiveGame, endpoint, &game); err != nil {
+		return nil, err
+	}
+
+	return &game, nil
+}
@@ -0,0 +1,51 @@
+package riot
+
+import "time"
+
+// マッチ詳細の永続キャッシュ
+// 終了した試合のデータは変化しないため、マッチIDをキーに保存して再取得を避ける
+type MatchStore interface {
+	// キャッシュ済みのマッチ詳細を取得（未保存の場合は ok=false）
+	GetMatch(matchID string) (detail *MatchDetail, ok bool, err error)
+
+	// 取得したマッチ詳細を保存
+	PutMatch(detail *MatchDetail) error
+}
+
+// マッチタイムラインの永続キャッシュ
+type TimelineStore interface {
+	// キャッシュ済みのタイムラインを取得（未保存の場合は ok=false）
+	GetTimeline(matchID string) (timeline *MatchTimeline, ok bool, err error)
+
+	// 取得したタイムラインを保存
+	PutTimeline(timeline *MatchTimeline) error
+}
+
+// プレイヤー・取得条件ごとの取得済みマッチID一覧
+type PlayerHistory struct {
+	PUUID     string    `json:"puuid"`
+	Query     string    `json:"query"`    // キュー・種別の条件を表すキー
+	MatchIDs  []string  `json:"matchIds"` // 新しい順
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// MatchIDs[0] の試合の開始時刻（差分同期の境界、マッチ詳細の取得前はゼロ値）
+	LatestGameStart time.Time `json:"latestGameStart,omitzero"`
+}
+
+// 差分同期のためのマッチ履歴の永続化
+type PlayerHistoryStore interface {
+	// 保存済みの履歴を取得（未保存の場合は ok=false）
+	GetPlayerHistory(puuid, query string) (history *PlayerHistory, ok bool, err error)
+
+	// 履歴を保存
+	PutPlayerHistory(history *PlayerHistory) error
+}
+
+// LP 推移を記録するランクのスナップショットの永続化
+type LeagueHistoryStore interface {
+	// 保存済みのスナップショットを古い順に取得（未保存の場合は空）
+	GetLeagueSnapshots(puuid string) ([]LeagueSnapshot, error)
+
+	// スナップショットを追加
+	PutLeagueSnapshot(snapshot *LeagueSnapshot) error
+}
@@ -0,0 +1,237 @@
+package riot
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// タイムラインイベントの種類
+const (
+	EventChampionKill            = "CHAMPION_KILL"
+	EventChampionSpecialKill     = "CHAMPION_SPECIAL_KILL"
+	EventWardPlaced              = "WARD_PLACED"
+	EventWardKill                = "WARD_KILL"
+	EventItemPurchased           = "ITEM_PURCHASED"
+	EventItemSold                = "ITEM_SOLD"
+	EventItemDestroyed           = "ITEM_DESTROYED"
+	EventItemUndo                = "ITEM_UNDO"
+	EventSkillLevelUp            = "SKILL_LEVEL_UP"
+	EventLevelUp                 = "LEVEL_UP"
+	EventEliteMonsterKill        = "ELITE_MONSTER_KILL"
+	EventBuildingKill            = "BUILDING_KILL"
+	EventTurretPlateDestroyed    = "TURRET_PLATE_DESTROYED"
+	EventDragonSoulGiven         = "DRAGON_SOUL_GIVEN"
+	EventChampionTransform       = "CHAMPION_TRANSFORM"
+	EventObjectiveBountyPrestart = "OBJECTIVE_BOUNTY_PRESTART"
+	EventPauseEnd                = "PAUSE_END"
+	EventGameEnd                 = "GAME_END"
+)
+
+// マッチタイムライン（match-v5 timeline）
+type MatchTimeline struct {
+	Metadata MatchMetadata `json:"metadata"`
+	Info     TimelineInfo  `json:"info"`
+}
+
+type TimelineInfo struct {
+	EndOfGameResult string                `json:"endOfGameResult"`
+	FrameInterval   int64                 `json:"frameInterval"` // ミリ秒（通常は60000）
+	GameID          int64                 `json:"gameId"`
+	Participants    []TimelineParticipant `json:"participants"`
+	Frames          []TimelineFrame       `json:"frames"`
+}
+
+type TimelineParticipant struct {
+	ParticipantID int    `json:"participantId"`
+	PUUID         string `json:"puuid"`
+}
+
+// 1分ごとのスナップショット
+type TimelineFrame struct {
+	Timestamp         int64                       `json:"timestamp"`         // ゲーム開始からのミリ秒
+	ParticipantFrames map[string]ParticipantFrame `json:"participantFrames"` // キーは参加者ID（"1"〜"10"）
+	Events            []TimelineEvent             `json:"events"`
+}
+
+// 参加者IDのスナップショットを取得
+func (f *TimelineFrame) Participant(participantID int) (ParticipantFrame, bool) {
+	frame, ok := f.ParticipantFrames[strconv.Itoa(participantID)]
+	return frame, ok
+}
+
+type ParticipantFrame struct {
+	ParticipantID            int           `json:"participantId"`
+	ChampionStats            ChampionStats `json:"championStats"`
+	DamageStats              DamageStats   `json:"damageStats"`
+	CurrentGold              int           `json:"currentGold"`
+	GoldPerSecond            int           `json:"goldPerSecond"`
+	TotalGold                int           `json:"totalGold"`
+	JungleMinionsKilled      int           `json:"jungleMinionsKilled"`
+	MinionsKilled            int           `json:"minionsKilled"`
+	Level                    int           `json:"level"`
+	XP                       int           `json:"xp"`
+	Position                 Position      `json:"position"`
+	TimeEnemySpentControlled int           `json:"timeEnemySpentControlled"`
+}
+
+// CS（ミニオン + 中立モンスター）
+func (p ParticipantFrame) CS() int {
+	return p.MinionsKilled + p.JungleMinionsKilled
+}
+
+type ChampionStats struct {
+	AbilityHaste         int `json:"abilityHaste"`
+	AbilityPower         int `json:"abilityPower"`
+	Armor                int `json:"armor"`
+	ArmorPen             int `json:"armorPen"`
+	ArmorPenPercent      int `json:"armorPenPercent"`
+	AttackDamage         int `json:"attackDamage"`
+	AttackSpeed          int `json:"attackSpeed"`
+	BonusArmorPenPercent int `json:"bonusArmorPenPercent"`
+	BonusMagicPenPercent int `json:"bonusMagicPenPercent"`
+	CCReduction          int `json:"ccReduction"`
+	CooldownReduction    int `json:"cooldownReduction"`
+	Health               int `json:"health"`
+	HealthMax            int `json:"healthMax"`
+	HealthRegen          int `json:"healthRegen"`
+	Lifesteal            int `json:"lifesteal"`
+	MagicPen             int `json:"magicPen"`
+	MagicPenPercent      int `json:"magicPenPercent"`
+	MagicResist          int `json:"magicResist"`
+	MovementSpeed        int `json:"movementSpeed"`
+	Omnivamp             int `json:"omnivamp"`
+	PhysicalVamp         int `json:"physicalVamp"`
+	Power                int `json:"power"`
+	PowerMax             int `json:"powerMax"`
+	PowerRegen           int `json:"powerRegen"`
+	SpellVamp            int `json:"spellVamp"`
+}
+
+type DamageStats struct {
+	MagicDamageDone               int `json:"magicDamageDone"`
+	MagicDamageDoneToChampions    int `json:"magicDamageDoneToChampions"`
+	MagicDamageTaken              int `json:"magicDamageTaken"`
+	PhysicalDamageDone            int `json:"physicalDamageDone"`
+	PhysicalDamageDoneToChampions int `json:"physicalDamageDoneToChampions"`
+	PhysicalDamageTaken           int `json:"physicalDamageTaken"`
+	TotalDamageDone               int `json:"totalDamageDone"`
+	TotalDamageDoneToChampions    int `json:"totalDamageDoneToChampions"`
+	TotalDamageTaken              int `json:"totalDamageTaken"`
+	TrueDamageDone                int `json:"trueDamageDone"`
+	TrueDamageDoneToChampions     int `json:"trueDamageDoneToChampions"`
+	TrueDamageTaken               int `json:"trueDamageTaken"`
+}
+
+// マップ上の座標
+type Position struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// タイムラインイベント
+// Type によって使われるフィールドが異なる（該当しないフィールドはゼロ値）
+type TimelineEvent struct {
+	Type          string `json:"type"`
+	Timestamp     int64  `json:"timestamp"` // ゲーム開始からのミリ秒
+	RealTimestamp int64  `json:"realTimestamp,omitempty"`
+
+	// 共通
+	ParticipantID int       `json:"participantId,omitempty"`
+	TeamID        int       `json:"teamId,omitempty"`
+	Position      *Position `json:"position,omitempty"`
+
+	// CHAMPION_KILL / CHAMPION_SPECIAL_KILL / ELITE_MONSTER_KILL / BUILDING_KILL
+	KillerID                int          `json:"killerId,omitempty"`
+	VictimID                int          `json:"victimId,omitempty"`
+	AssistingParticipantIDs []int        `json:"assistingParticipantIds,omitempty"`
+	Bounty                  int          `json:"bounty,omitempty"`
+	ShutdownBounty          int          `json:"shutdownBounty,omitempty"`
+	KillStreakLength        int          `json:"killStreakLength,omitempty"`
+	KillType                string       `json:"killType,omitempty"` // KILL_FIRST_BLOOD / KILL_MULTI / KILL_ACE
+	MultiKillLength         int          `json:"multiKillLength,omitempty"`
+	VictimDamageDealt       []DamageInfo `json:"victimDamageDealt,omitempty"`
+	VictimDamageReceived    []DamageInfo `json:"victimDamageReceived,omitempty"`
+
+	// ELITE_MONSTER_KILL
+	KillerTeamID   int    `json:"killerTeamId,omitempty"`
+	MonsterType    string `json:"monsterType,omitempty"`    // DRAGON / BARON_NASHOR / RIFTHERALD / HORDE など
+	MonsterSubType string `json:"monsterSubType,omitempty"` // FIRE_DRAGON など
+
+	// BUILDING_KILL / TURRET_PLATE_DESTROYED
+	BuildingType string `json:"buildingType,omitempty"` // TOWER_BUILDING / INHIBITOR_BUILDING
+	TowerType    string `json:"towerType,omitempty"`    // OUTER_TURRET / INNER_TURRET など
+	LaneType     string `json:"laneType,omitempty"`     // TOP_LANE / MID_LANE / BOT_LANE
+
+	// ITEM_PURCHASED / ITEM_SOLD / ITEM_DESTROYED / ITEM_UNDO
+	ItemID   int `json:"itemId,omitempty"`
+	BeforeID int `json:"beforeId,omitempty"`
+	AfterID  int `json:"afterId,omitempty"`
+	GoldGain int `json:"goldGain,omitempty"`
+
+	// WARD_PLACED / WARD_KILL
+	CreatorID int    `json:"creatorId,omitempty"`
+	WardType  string `json:"wardType,omitempty"` // YELLOW_TRINKET / CONTROL_WARD / SIGHT_WARD など
+
+	// LEVEL_UP / SKILL_LEVEL_UP
+	Level       int    `json:"level,omitempty"`
+	LevelUpType string `json:"levelUpType,omitempty"`
+	SkillSlot   int    `json:"skillSlot,omitempty"`
+
+	// DRAGON_SOUL_GIVEN
+	Name string `json:"name,omitempty"`
+
+	// CHAMPION_TRANSFORM
+	TransformType string `json:"transformType,omitempty"`
+
+	// GAME_END
+	GameID      int64 `json:"gameId,omitempty"`
+	WinningTeam int   `json:"winningTeam,omitempty"`
+}
+
+// キル時のダメージ内訳
+type DamageInfo struct {
+	Basic          bool   `json:"basic"`
+	MagicDamage    int    `json:"magicDamage"`
+	Name           string `json:"name"`
+	ParticipantID  int    `json:"participantId"`
+	PhysicalDamage int    `json:"physicalDamage"`
+	SpellName      string `json:"spellName"`
+	SpellSlot      int    `json:"spellSlot"`
+	TrueDamage     int    `json:"trueDamage"`
+	Type           string `json:"type"`
+}
+
+// マッチタイムライン取得（キャッシュ・レート制限対応）
+func (c *Client) GetMatchTimelineWithContext(ctx context.Context, matchID string) (*MatchTimeline, error) {
+	// キャッシュを優先して参照
+	if c.TimelineStore != nil {
+		cached, ok, err := c.TimelineStore.GetTimeline(matchID)
+		if err != nil {
+			c.report(ctx, ProgressEvent{Type: ProgressWarning, Phase: PhaseTimelines, MatchID: matchID, Message: "キャッシュの読み込みに失敗", Err: err})
+		} else if ok {
+			return cached, nil
+		}
+	}
+
+	routing, err := c.regionalRouting()
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/lol/match/v5/matches/%s/timeline", url.PathEscape(matchID))
+
+	var timeline MatchTimeline
+	if err := c.getJSON(ctx, routing, MethodMatchTimeline, endpoint, &timeline); err != nil {
+		return nil, err
+	}
+
+	// 取得に成功したタイムラインをキャッシュに保存
+	if c.TimelineStore != nil {
+		if err := c.TimelineStore.PutTimeline(&timeline); err != nil {
+			c.report(ctx, ProgressEvent{Type: ProgressWarning, Phase: PhaseTimelines, MatchID: matchID, Message: "キャッシュの保存に失敗", Err: err})
+		}
+	}
+
+	return &timeline, nil
+}
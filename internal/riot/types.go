@@ -63,6 +63,13 @@ type Participant struct {
 	GoldEarned                     int    `json:"goldEarned"`
 	GoldSpent                      int    `json:"goldSpent"`
 	IndividualPosition             string `json:"individualPosition"`
+	Item0                          int    `json:"item0"`
+	Item1                          int    `json:"item1"`
+	Item2                          int    `json:"item2"`
+	Item3                          int    `json:"item3"`
+	Item4                          int    `json:"item4"`
+	Item5                          int    `json:"item5"`
+	Item6                          int    `json:"item6"` // トリンケット
 	Kills                          int    `json:"kills"`
 	Lane                           string `json:"lane"`
 	LargestCriticalStrike          int    `json:"largestCriticalStrike"`
@@ -86,6 +93,8 @@ type Participant struct {
 	RiotIDTagline                  string `json:"riotIdTagline"`
 	Role                           string `json:"role"`
 	SightWardsBoughtInGame         int    `json:"sightWardsBoughtInGame"`
+	Summoner1ID                    int    `json:"summoner1Id"`
+	Summoner2ID                    int    `json:"summoner2Id"`
 	TeamEarlySurrendered           bool   `json:"teamEarlySurrendered"`
 	TeamID                         int    `json:"teamId"`
 	TeamPosition                   string `json:"teamPosition"`
@@ -113,6 +122,38 @@ type Participant struct {
 	WardsKilled                    int    `json:"wardsKilled"`
 	WardsPlaced                    int    `json:"wardsPlaced"`
 	Win                            bool   `json:"win"`
+
+	Perks MatchPerks `json:"perks"` // 使用したルーン
+}
+
+// 装備品（アイテム0〜6、空き枠は0）
+func (p *Participant) Items() [7]int {
+	return [7]int{p.Item0, p.Item1, p.Item2, p.Item3, p.Item4, p.Item5, p.Item6}
+}
+
+// 試合で使用したルーン（Styles はメイン・サブのパスの順）
+type MatchPerks struct {
+	StatPerks PerkStats   `json:"statPerks"`
+	Styles    []PerkStyle `json:"styles"`
+}
+
+type PerkStats struct {
+	Defense int `json:"defense"`
+	Flex    int `json:"flex"`
+	Offense int `json:"offense"`
+}
+
+type PerkStyle struct {
+	Description string          `json:"description"` // primaryStyle / subStyle
+	Selections  []PerkSelection `json:"selections"`
+	Style       int             `json:"style"` // パスのID
+}
+
+type PerkSelection struct {
+	Perk int `json:"perk"`
+	Var1 int `json:"var1"`
+	Var2 int `json:"var2"`
+	Var3 int `json:"var3"`
 }
 
 type Team struct {
@@ -148,4 +189,16 @@ type PlayerMatchSummary struct {
 	GeneratedAt  time.Time     `json:"generatedAt"`
 	TotalMatches int           `json:"totalMatches"`
 	MatchType    string        `json:"matchType"`
+
+	// 現在のランク（未ランクのキューは含まれない）
+	LeagueEntries []LeagueEntry `json:"leagueEntries"`
+
+	// 保存済みのランクのスナップショット（古い順、LP 推移の計算用）
+	LeagueHistory []LeagueSnapshot `json:"leagueHistory,omitempty"`
+
+	// チャンピオンマスタリー（ポイントの多い順）
+	ChampionMasteries []ChampionMastery `json:"championMasteries,omitempty"`
+
+	// マッチIDごとのタイムライン（IncludeTimelines 指定時のみ、詳細データには出力しない）
+	Timelines map[string]*MatchTimeline `json:"-"`
 }
@@ -0,0 +1,167 @@
+package staticdata
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// アーカイブから取り込む条件
+type ImportOptions struct {
+	Locales []string // 取り込む言語（空の場合はすべて）
+	Images  bool     // 画像も取り込む（アーカイブの大半を占めるため既定では取り込まない）
+}
+
+// 取り込み結果
+type ImportResult struct {
+	Versions []string
+	Files    int
+}
+
+// 取り込むデータファイル（championFull.json や champion/ 以下の個別ファイルは使わない）
+var importDataFiles = []string{championFile, itemFile, runeFile, summonerFile, mapFile}
+
+// 取り込む画像のディレクトリ（<version>/img/ 以下）
+var importImageGroups = []string{"champion", "item", "spell", "map", "profileicon", "passive"}
+
+// Data Dragon のアーカイブ（dragontail-<version>.tgz）を dir に展開
+func ImportArchive(archivePath, dir string, opts ImportOptions) (*ImportResult, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("アーカイブ読み込みエラー: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("アーカイブ展開エラー (%s): %w", archivePath, err)
+	}
+	defer gz.Close()
+
+	result := &ImportResult{}
+	tr := tar.NewReader(gz)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("アーカイブ展開エラー (%s): %w", archivePath, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// アーカイブ外へのパスを含むアーカイブは展開しない
+		name := strings.TrimPrefix(path.Clean(header.Name), "./")
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			return nil, fmt.Errorf("アーカイブに不正なパスが含まれています: %s", header.Name)
+		}
+		version, ok := opts.target(name)
+		if !ok {
+			continue
+		}
+
+		if err := writeFile(filepath.Join(dir, filepath.FromSlash(name)), tr); err != nil {
+			return nil, err
+		}
+		result.Files++
+		if version != "" && !slices.Contains(result.Versions, version) {
+			result.Versions = append(result.Versions, version)
+		}
+	}
+
+	if len(result.Versions) == 0 {
+		return nil, fmt.Errorf("Data Dragon のデータが見つかりません: %s", archivePath)
+	}
+
+	return result, nil
+}
+
+// 開発者向け静的データ（queues.json / maps.json）を dir にコピー
+func ImportStaticFile(filePath, dir string) error {
+	name := filepath.Base(filePath)
+	if name != queuesFile && name != mapsFile {
+		return fmt.Errorf("未対応のファイルです（%s または %s を指定）: %s", queuesFile, mapsFile, filePath)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("静的データ読み込みエラー: %w", err)
+	}
+
+	// 形式を確認してから保存（Open 時の読み込みエラーを防ぐ）
+	var entries []json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("静的データJSON解析エラー (%s): %w", filePath, err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("静的データディレクトリ作成エラー: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("静的データ保存エラー: %w", err)
+	}
+	return nil
+}
+
+// アーカイブ内のパスが取り込み対象か判定（対象の場合はバージョンを返す、ルーン画像は空）
+//
+//	<version>/data/<locale>/<file>.json
+//	<version>/img/<group>/<file>
+//	img/perk-images/...
+func (o ImportOptions) target(name string) (string, bool) {
+	parts := strings.Split(name, "/")
+
+	if o.Images && len(parts) > 2 && parts[0] == "img" && parts[1] == "perk-images" {
+		return "", true
+	}
+
+	if len(parts) < 2 {
+		return "", false
+	}
+	if _, ok := ParseVersion(parts[0]); !ok {
+		return "", false
+	}
+	version := parts[0]
+
+	switch {
+	case len(parts) == 4 && parts[1] == "data":
+		if len(o.Locales) > 0 && !slices.Contains(o.Locales, parts[2]) {
+			return "", false
+		}
+		return version, slices.Contains(importDataFiles, parts[3])
+	case len(parts) == 4 && parts[1] == "img":
+		return version, o.Images && slices.Contains(importImageGroups, parts[2])
+	default:
+		return "", false
+	}
+}
+
+func writeFile(filePath string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return fmt.Errorf("静的データディレクトリ作成エラー: %w", err)
+	}
+
+	f, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("静的データ保存エラー: %w", err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("静的データ保存エラー: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("静的データ保存エラー: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package staticdata
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// 指定したパスのファイルを含む dragontail 形式のアーカイブを作成
+func writeTestArchive(t *testing.T, names []string) string {
+	t.Helper()
+
+	archivePath := filepath.Join(t.TempDir(), "dragontail.tgz")
+	f, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, name := range names {
+		content := []byte("{}")
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("WriteHeader: %v", err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("tar Close: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gzip Close: %v", err)
+	}
+
+	return archivePath
+}
+
+// 展開先のファイル一覧（/ 区切り）
+func extractedFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkDir: %v", err)
+	}
+	slices.Sort(files)
+	return files
+}
+
+func TestImportArchive(t *testing.T) {
+	archive := []string{
+		"./15.20.1/data/ja_JP/champion.json",
+		"15.20.1/data/ja_JP/item.json",
+		"15.20.1/data/ja_JP/championFull.json",
+		"15.20.1/data/ja_JP/champion/Ahri.json",
+		"15.20.1/data/en_US/champion.json",
+		"15.20.1/img/champion/Ahri.png",
+		"15.20.1/img/splash/Ahri_0.jpg",
+		"img/perk-images/Styles/Domination/Electrocute/Electrocute.png",
+		"img/champion/splash/Ahri_0.jpg",
+		"lolpatch_7.17/data/ja_JP/champion.json",
+		"languages.json",
+	}
+
+	tests := []struct {
+		name string
+		opts ImportOptions
+		want []string
+	}{
+		{
+			name: "指定した言語のデータのみ",
+			opts: ImportOptions{Locales: []string{"ja_JP"}},
+			want: []string{"15.20.1/data/ja_JP/champion.json", "15.20.1/data/ja_JP/item.json"},
+		},
+		{
+			name: "すべての言語",
+			want: []string{"15.20.1/data/en_US/champion.json", "15.20.1/data/ja_JP/champion.json", "15.20.1/data/ja_JP/item.json"},
+		},
+		{
+			name: "画像も取り込む",
+			opts: ImportOptions{Locales: []string{"ja_JP"}, Images: true},
+			want: []string{
+				"15.20.1/data/ja_JP/champion.json",
+				"15.20.1/data/ja_JP/item.json",
+				"15.20.1/img/champion/Ahri.png",
+				"img/perk-images/Styles/Domination/Electrocute/Electrocute.png",
+			},
+		},
+	}
+
+	archivePath := writeTestArchive(t, archive)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			result, err := ImportArchive(archivePath, dir, tt.opts)
+			if err != nil {
+				t.Fatalf("ImportArchive: %v", err)
+			}
+			if !slices.Equal(result.Versions, []string{"15.20.1"}) {
+				t.Errorf("Versions = %v, want [15.20.1]", result.Versions)
+			}
+			if result.Files != len(tt.want) {
+				t.Errorf("Files = %d, want %d", result.Files, len(tt.want))
+			}
+			if got := extractedFiles(t, dir); !slices.Equal(got, tt.want) {
+				t.Errorf("展開したファイル = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportArchiveInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+	}{
+		{name: "親ディレクトリへのパス", names: []string{"15.20.1/data/ja_JP/champion.json", "../15.20.1/data/ja_JP/item.json"}},
+		{name: "途中で親ディレクトリに戻るパス", names: []string{"img/perk-images/../../../../evil.png"}},
+		{name: "絶対パス", names: []string{"/15.20.1/data/ja_JP/champion.json"}},
+		{name: "Data Dragon のデータなし", names: []string{"languages.json", "img/champion/splash/Ahri_0.jpg"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			dir := filepath.Join(root, "staticdata")
+			archivePath := writeTestArchive(t, tt.names)
+
+			if _, err := ImportArchive(archivePath, dir, ImportOptions{Images: true}); err == nil {
+				t.Fatal("ImportArchive: want error")
+			}
+
+			// 展開先の外にはファイルを作成しない
+			for _, file := range extractedFiles(t, root) {
+				if !strings.HasPrefix(file, "staticdata/") {
+					t.Errorf("展開先の外に作成: %s", file)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+// Package staticdata はローカルに保存した Data Dragon のスナップショットを読み込み、
+// チャンピオン・アイテム・ルーン・サモナースペル・キュー・マップの ID を名前やアイコンに変換する
+//
+// ディレクトリ構成（Import で作成）:
+//
+//	<dir>/<version>/data/<locale>/champion.json など  Data Dragon のデータ
+//	<dir>/<version>/img/...                           画像（取り込んだ場合のみ）
+//	<dir>/img/perk-images/...                         ルーンの画像（取り込んだ場合のみ）
+//	<dir>/queues.json, <dir>/maps.json                 開発者向け静的データ（任意）
+package staticdata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// スナップショットが1つも取り込まれていない
+var ErrNoSnapshot = errors.New("Data Dragon のスナップショットがありません")
+
+// 読み込むデータファイル
+const (
+	championFile = "champion.json"
+	itemFile     = "item.json"
+	runeFile     = "runesReforged.json"
+	summonerFile = "summoner.json"
+	mapFile      = "map.json"
+	queuesFile   = "queues.json"
+	mapsFile     = "maps.json"
+)
+
+// 1バージョン・1言語分の静的データ
+type Snapshot struct {
+	Version string
+	Locale  string
+
+	Champions      map[int]*Champion // 数値ID -> チャンピオン
+	Items          map[int]*Item
+	RuneStyles     map[int]*RuneStyle
+	Runes          map[int]*Rune
+	SummonerSpells map[int]*SummonerSpell
+	Maps           map[int]*Map
+}
+
+// 取り込み済みのスナップショット（バージョンごとに必要になった時点で読み込む）
+type Store struct {
+	dir      string
+	locale   string
+	versions []Version // 新しい順
+
+	queues map[int]Queue
+	maps   map[int]Map
+
+	mu        sync.Mutex
+	snapshots map[string]*Snapshot
+}
+
+// ディレクトリ内のスナップショットを列挙（データの読み込みは Snapshot 取得時）
+func Open(dir, locale string) (*Store, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("静的データディレクトリ読み込みエラー: %w", err)
+	}
+
+	s := &Store{
+		dir:       dir,
+		locale:    locale,
+		queues:    make(map[int]Queue),
+		maps:      make(map[int]Map),
+		snapshots: make(map[string]*Snapshot),
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		version, ok := ParseVersion(entry.Name())
+		if !ok {
+			continue
+		}
+		// 指定した言語のデータがないバージョンは対象外
+		if _, err := os.Stat(filepath.Join(dir, entry.Name(), "data", locale, championFile)); err != nil {
+			continue
+		}
+		s.versions = append(s.versions, version)
+	}
+	slices.SortFunc(s.versions, func(a, b Version) int { return b.Compare(a) })
+
+	var queues []Queue
+	if err := readOptionalJSON(filepath.Join(dir, queuesFile), &queues); err != nil {
+		return nil, err
+	}
+	for _, queue := range queues {
+		s.queues[queue.QueueID] = queue
+	}
+
+	var maps []Map
+	if err := readOptionalJSON(filepath.Join(dir, mapsFile), &maps); err != nil {
+		return nil, err
+	}
+	for _, m := range maps {
+		s.maps[m.MapID] = m
+	}
+
+	return s, nil
+}
+
+// 静的データのディレクトリ
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// 取り込み済みのバージョン（新しい順）
+func (s *Store) Versions() []string {
+	versions := make([]string, len(s.versions))
+	for i, version := range s.versions {
+		versions[i] = version.String()
+	}
+	return versions
+}
+
+// 最新のスナップショット
+func (s *Store) Latest() (*Snapshot, error) {
+	if len(s.versions) == 0 {
+		return nil, ErrNoSnapshot
+	}
+	return s.Snapshot(s.versions[0].String())
+}
+
+// 試合のバージョン（match-v5 の gameVersion、例: "14.10.584.8247"）に対応するスナップショット
+// 同じパッチがない場合はそれ以前で最新のもの、それもない場合は最も古いものを返す
+func (s *Store) ForGameVersion(gameVersion string) (*Snapshot, error) {
+	if len(s.versions) == 0 {
+		return nil, ErrNoSnapshot
+	}
+
+	patch, ok := ParseVersion(gameVersion)
+	if !ok {
+		return s.Latest()
+	}
+
+	// versions は新しい順のため最初に見つかったものが同じパッチ内の最新
+	for _, version := range s.versions {
+		if version.Major < patch.Major || (version.Major == patch.Major && version.Minor <= patch.Minor) {
+			return s.Snapshot(version.String())
+		}
+	}
+	return s.Snapshot(s.versions[len(s.versions)-1].String())
+}
+
+// 指定したバージョンのスナップショット（読み込み済みの場合は再利用）
+func (s *Store) Snapshot(version string) (*Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if snapshot, ok := s.snapshots[version]; ok {
+		return snapshot, nil
+	}
+
+	snapshot, err := s.load(version)
+	if err != nil {
+		return nil, err
+	}
+	s.snapshots[version] = snapshot
+	return snapshot, nil
+}
+
+// キュー情報（queues.json を取り込んでいない場合は ok=false）
+func (s *Store) Queue(queueID int) (Queue, bool) {
+	queue, ok := s.queues[queueID]
+	return queue, ok
+}
+
+func (s *Store) load(version string) (*Snapshot, error) {
+	dataDir := filepath.Join(s.dir, version, "data", s.locale)
+
+	snapshot := &Snapshot{
+		Version:        version,
+		Locale:         s.locale,
+		Champions:      make(map[int]*Champion),
+		Items:          make(map[int]*Item),
+		RuneStyles:     make(map[int]*RuneStyle),
+		Runes:          make(map[int]*Rune),
+		SummonerSpells: make(map[int]*SummonerSpell),
+		Maps:           make(map[int]*Map),
+	}
+
+	var champions struct {
+		Data map[string]*Champion `json:"data"`
+	}
+	if err := readJSON(filepath.Join(dataDir, championFile), &champions); err != nil {
+		return nil, err
+	}
+	for _, champion := range champions.Data {
+		snapshot.Champions[parseKey(champion.Key)] = champion
+	}
+
+	var items struct {
+		Data map[string]*Item `json:"data"`
+	}
+	if err := readOptionalJSON(filepath.Join(dataDir, itemFile), &items); err != nil {
+		return nil, err
+	}
+	for id, item := range items.Data {
+		item.ID = parseKey(id)
+		snapshot.Items[item.ID] = item
+	}
+
+	var styles []*RuneStyle
+	if err := readOptionalJSON(filepath.Join(dataDir, runeFile), &styles); err != nil {
+		return nil, err
+	}
+	for _, style := range styles {
+		snapshot.RuneStyles[style.ID] = style
+		for i := range style.Slots {
+			for j := range style.Slots[i].Runes {
+				perk := &style.Slots[i].Runes[j]
+				perk.StyleID = style.ID
+				snapshot.Runes[perk.ID] = perk
+			}
+		}
+	}
+
+	var spells struct {
+		Data map[string]*SummonerSpell `json:"data"`
+	}
+	if err := readOptionalJSON(filepath.Join(dataDir, summonerFile), &spells); err != nil {
+		return nil, err
+	}
+	for _, spell := range spells.Data {
+		snapshot.SummonerSpells[parseKey(spell.Key)] = spell
+	}
+
+	// Data Dragon の map.json はキー名が異なる（MapId は文字列）
+	var maps struct {
+		Data map[string]struct {
+			MapName string `json:"MapName"`
+			MapID   string `json:"MapId"`
+			Image   Image  `json:"image"`
+		} `json:"data"`
+	}
+	if err := readOptionalJSON(filepath.Join(dataDir, mapFile), &maps); err != nil {
+		return nil, err
+	}
+	for _, m := range maps.Data {
+		image := m.Image
+		snapshot.Maps[parseKey(m.MapID)] = &Map{MapID: parseKey(m.MapID), MapName: m.MapName, Image: &image}
+	}
+	// map.json にないマップは maps.json で補う
+	for id, m := range s.maps {
+		if _, ok := snapshot.Maps[id]; !ok {
+			snapshot.Maps[id] = &m
+		}
+	}
+
+	return snapshot, nil
+}
+
+// チャンピオン名（不明な場合は数値ID）
+func (s *Snapshot) ChampionName(championID int) string {
+	if champion, ok := s.Champions[championID]; ok {
+		return champion.Name
+	}
+	return strconv.Itoa(championID)
+}
+
+// アイテム名（不明な場合は数値ID）
+func (s *Snapshot) ItemName(itemID int) string {
+	if item, ok := s.Items[itemID]; ok {
+		return item.Name
+	}
+	return strconv.Itoa(itemID)
+}
+
+// ルーン名（パスのIDも受け付ける、不明な場合は数値ID）
+func (s *Snapshot) RuneName(runeID int) string {
+	if perk, ok := s.Runes[runeID]; ok {
+		return perk.Name
+	}
+	if style, ok := s.RuneStyles[runeID]; ok {
+		return style.Name
+	}
+	return strconv.Itoa(runeID)
+}
+
+// サモナースペル名（不明な場合は数値ID）
+func (s *Snapshot) SummonerSpellName(spellID int) string {
+	if spell, ok := s.SummonerSpells[spellID]; ok {
+		return spell.Name
+	}
+	return strconv.Itoa(spellID)
+}
+
+// 画像ファイルの静的データのディレクトリからの相対パス（/ 区切り、画像を取り込んでいない場合は存在しない）
+// ディレクトリを配信するURLやディレクトリのパスと path.Join で結合して使う
+func (s *Snapshot) ImageURL(image Image) string {
+	return path.Join(s.Version, "img", image.Group, image.Full)
+}
+
+// ルーン画像の静的データのディレクトリからの相対パス（ルーンの画像はバージョンに依存しない img/ 以下にある）
+func (s *Snapshot) RuneIconURL(icon string) string {
+	return path.Join("img", icon)
+}
+
+func readJSON(path string, out any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("静的データ読み込みエラー: %w", err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("静的データJSON解析エラー (%s): %w", path, err)
+	}
+	return nil
+}
+
+// ファイルがない場合は何もしない
+func readOptionalJSON(path string, out any) error {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return readJSON(path, out)
+}
@@ -0,0 +1,75 @@
+package staticdata
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 指定したバージョンのスナップショット（チャンピオン名にバージョンを含める）を作成
+func writeTestSnapshot(t *testing.T, dir, version, locale string) {
+	t.Helper()
+
+	dataDir := filepath.Join(dir, version, "data", locale)
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	content := `{"data":{"Ahri":{"id":"Ahri","key":"103","name":"Ahri ` + version + `","image":{"full":"Ahri.png","group":"champion"}}}}`
+	if err := os.WriteFile(filepath.Join(dataDir, championFile), []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+func TestForGameVersion(t *testing.T) {
+	dir := t.TempDir()
+	for _, version := range []string{"14.10.1", "15.19.1", "15.20.1", "15.20.2"} {
+		writeTestSnapshot(t, dir, version, "ja_JP")
+	}
+	// 指定した言語のデータがないバージョンは対象外
+	writeTestSnapshot(t, dir, "15.21.1", "en_US")
+
+	store, err := Open(dir, "ja_JP")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		gameVersion string
+		want        string
+	}{
+		{name: "同じパッチ", gameVersion: "15.19.715.1836", want: "15.19.1"},
+		{name: "同じパッチの最新", gameVersion: "15.20.717.2831", want: "15.20.2"},
+		{name: "以前のパッチ", gameVersion: "15.1.1.1", want: "14.10.1"},
+		{name: "取り込んでいないパッチはそれ以前で最新", gameVersion: "15.21.1.1", want: "15.20.2"},
+		{name: "それ以前がない場合は最も古いもの", gameVersion: "13.24.1.1", want: "14.10.1"},
+		{name: "解析できないバージョンは最新", gameVersion: "lolpatch_7.17", want: "15.20.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot, err := store.ForGameVersion(tt.gameVersion)
+			if err != nil {
+				t.Fatalf("ForGameVersion(%q): %v", tt.gameVersion, err)
+			}
+			if snapshot.Version != tt.want {
+				t.Errorf("ForGameVersion(%q) = %s, want %s", tt.gameVersion, snapshot.Version, tt.want)
+			}
+			if name := snapshot.ChampionName(103); name != "Ahri "+tt.want {
+				t.Errorf("ChampionName = %s, want %s のデータ", name, tt.want)
+			}
+		})
+	}
+}
+
+func TestForGameVersionNoSnapshot(t *testing.T) {
+	store, err := Open(t.TempDir(), "ja_JP")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	if _, err := store.ForGameVersion("15.20.717.2831"); !errors.Is(err, ErrNoSnapshot) {
+		t.Errorf("err = %v, want %v", err, ErrNoSnapshot)
+	}
+}
@@ -0,0 +1,99 @@
+package staticdata
+
+import "strconv"
+
+// Data Dragon の画像情報（スプライト内の位置を含む）
+type Image struct {
+	Full   string `json:"full"`
+	Sprite string `json:"sprite"`
+	Group  string `json:"group"` // champion / item / spell / map など（img/ 以下のディレクトリ名）
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	W      int    `json:"w"`
+	H      int    `json:"h"`
+}
+
+// チャンピオン（champion.json）
+type Champion struct {
+	ID    string   `json:"id"`  // 内部名（Kaisa など、match-v5 の championName と同じ）
+	Key   string   `json:"key"` // 数値ID の文字列（match-v5・spectator-v5 の championId）
+	Name  string   `json:"name"`
+	Title string   `json:"title"`
+	Tags  []string `json:"tags"`
+	Image Image    `json:"image"`
+}
+
+// アイテム（item.json、ID はマップのキー）
+type Item struct {
+	ID          int      `json:"-"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Plaintext   string   `json:"plaintext"`
+	Into        []string `json:"into"`
+	From        []string `json:"from"`
+	Tags        []string `json:"tags"`
+	Gold        ItemGold `json:"gold"`
+	Image       Image    `json:"image"`
+}
+
+type ItemGold struct {
+	Base        int  `json:"base"`
+	Total       int  `json:"total"`
+	Sell        int  `json:"sell"`
+	Purchasable bool `json:"purchasable"`
+}
+
+// ルーンのパス（runesReforged.json）
+type RuneStyle struct {
+	ID    int        `json:"id"`
+	Key   string     `json:"key"`
+	Name  string     `json:"name"`
+	Icon  string     `json:"icon"` // img/ 以下のパス（perk-images/...）
+	Slots []RuneSlot `json:"slots"`
+}
+
+type RuneSlot struct {
+	Runes []Rune `json:"runes"`
+}
+
+type Rune struct {
+	ID        int    `json:"id"`
+	Key       string `json:"key"`
+	Name      string `json:"name"`
+	Icon      string `json:"icon"`
+	ShortDesc string `json:"shortDesc"`
+	StyleID   int    `json:"-"` // 所属するパスのID
+}
+
+// サモナースペル（summoner.json）
+type SummonerSpell struct {
+	ID          string    `json:"id"`  // 内部名（SummonerFlash など）
+	Key         string    `json:"key"` // 数値ID の文字列（spell1Id / summoner1Id）
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Cooldown    []float64 `json:"cooldown"`
+	Modes       []string  `json:"modes"`
+	Image       Image     `json:"image"`
+}
+
+// マップ（map.json、または開発者向け静的データの maps.json）
+type Map struct {
+	MapID   int    `json:"mapId"`
+	MapName string `json:"mapName"`
+	Notes   string `json:"notes,omitempty"`
+	Image   *Image `json:"image,omitempty"` // Data Dragon の map.json にのみ含まれる
+}
+
+// キュー（開発者向け静的データの queues.json）
+type Queue struct {
+	QueueID     int    `json:"queueId"`
+	Map         string `json:"map"`
+	Description string `json:"description"`
+	Notes       string `json:"notes"`
+}
+
+// 数値IDの文字列を変換（不正な値は0）
+func parseKey(key string) int {
+	n, _ := strconv.Atoi(key)
+	return n
+}
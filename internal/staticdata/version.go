@@ -0,0 +1,47 @@
+package staticdata
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Data Dragon のバージョン（例: 14.10.1）
+// 試合の gameVersion（例: 14.10.584.8247）も先頭3要素を同じ形で扱う
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// "14.10.1" や "14.10.584.8247" を解析（"lolpatch_7.17" など数値でないものは ok=false）
+func ParseVersion(s string) (Version, bool) {
+	parts := strings.Split(s, ".")
+	if len(parts) < 2 {
+		return Version{}, false
+	}
+
+	var nums [3]int
+	for i := 0; i < len(parts) && i < len(nums); i++ {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil || n < 0 {
+			return Version{}, false
+		}
+		nums[i] = n
+	}
+
+	return Version{Major: nums[0], Minor: nums[1], Patch: nums[2]}, true
+}
+
+func (v Version) Compare(other Version) int {
+	if c := cmp.Compare(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	return cmp.Compare(v.Patch, other.Patch)
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
@@ -0,0 +1,368 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+// ファイルベースのローカルストア（1試合1ファイル）
+type FileStore struct {
+	dir        string
+	maxMatches int // 保存する最大試合数（0以下で無制限）
+
+	// 索引と統計のみを保護する（ファイルの読み書きはロックの外で行う）
+	mu        sync.Mutex
+	entries   *lruIndex // 保存済みのマッチ詳細
+	timelines *lruIndex // 保存済みのタイムライン
+	hits      int64
+	misses    int64
+
+	// プレイヤー履歴・ランク履歴の読み書きを直列化する（追記は読み込みと書き込みの間も保持）
+	historyMu sync.Mutex
+}
+
+// キャッシュの利用状況
+type Stats struct {
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+	Entries int   `json:"entries"`
+
+	// タイムラインの保存件数
+	TimelineEntries int `json:"timelineEntries"`
+}
+
+func NewFileStore(dir string, maxMatches int) (*FileStore, error) {
+	s := &FileStore{
+		dir:        dir,
+		maxMatches: maxMatches,
+		entries:    newLRUIndex(),
+		timelines:  newLRUIndex(),
+	}
+
+	for _, dir := range []string{s.matchDir(), s.timelineDir(), s.playerDir(), s.leagueDir()} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("ストアディレクトリ作成エラー: %w", err)
+		}
+	}
+
+	// 既存のキャッシュを索引に読み込む
+	if err := loadIndex(s.matchDir(), s.entries); err != nil {
+		return nil, err
+	}
+	if err := loadIndex(s.timelineDir(), s.timelines); err != nil {
+		return nil, err
+	}
+
+	removeFiles(s.evictLocked())
+
+	return s, nil
+}
+
+func (s *FileStore) matchDir() string {
+	return filepath.Join(s.dir, "matches")
+}
+
+func (s *FileStore) matchPath(matchID string) string {
+	return filepath.Join(s.matchDir(), url.PathEscape(matchID)+".json")
+}
+
+func (s *FileStore) timelineDir() string {
+	return filepath.Join(s.dir, "timelines")
+}
+
+func (s *FileStore) timelinePath(matchID string) string {
+	return filepath.Join(s.timelineDir(), url.PathEscape(matchID)+".json")
+}
+
+func (s *FileStore) playerDir() string {
+	return filepath.Join(s.dir, "players")
+}
+
+func (s *FileStore) playerPath(puuid, query string) string {
+	return filepath.Join(s.playerDir(), url.PathEscape(puuid), url.PathEscape(query)+".json")
+}
+
+func (s *FileStore) leagueDir() string {
+	return filepath.Join(s.dir, "league")
+}
+
+func (s *FileStore) leaguePath(puuid string) string {
+	return filepath.Join(s.leagueDir(), url.PathEscape(puuid)+".json")
+}
+
+// キャッシュ済みのマッチ詳細を取得
+func (s *FileStore) GetMatch(matchID string) (*riot.MatchDetail, bool, error) {
+	var detail riot.MatchDetail
+	ok, err := s.readCached(s.entries, s.matchPath(matchID), matchID, &detail)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return &detail, true, nil
+}
+
+// 索引に登録済みのファイルを読み込んで out にデコード
+// 読み込み中はロックを保持しないため、キャッシュヒット時も並行して読み込める
+func (s *FileStore) readCached(index *lruIndex, path, matchID string, out any) (bool, error) {
+	s.mu.Lock()
+	exists := index.contains(matchID)
+	if !exists {
+		s.misses++
+	}
+	s.mu.Unlock()
+
+	if !exists {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, fmt.Errorf("キャッシュ読み込みエラー: %w", err)
+	}
+	if err == nil {
+		if err = json.Unmarshal(data, out); err != nil {
+			// 壊れたファイルは破棄して再取得させる
+			os.Remove(path)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		// 外部から削除された場合や壊れていた場合は索引からも除外
+		index.remove(matchID)
+		s.misses++
+		return false, nil
+	}
+
+	// 読み込み中に削除された場合は索引に戻さない
+	if index.contains(matchID) {
+		index.touch(matchID)
+	}
+	s.hits++
+	return true, nil
+}
+
+// マッチ詳細を保存（上限を超えた場合は最も長く使われていないものから削除）
+func (s *FileStore) PutMatch(detail *riot.MatchDetail) error {
+	matchID := detail.Metadata.MatchID
+	if matchID == "" {
+		return fmt.Errorf("マッチIDが空のため保存できません")
+	}
+
+	data, err := json.Marshal(detail)
+	if err != nil {
+		return fmt.Errorf("JSON変換エラー: %w", err)
+	}
+
+	if err := writeFileAtomic(s.matchPath(matchID), data); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.entries.touch(matchID)
+	evicted := s.evictLocked()
+	s.mu.Unlock()
+
+	removeFiles(evicted)
+	return nil
+}
+
+// キャッシュ済みのタイムラインを取得
+func (s *FileStore) GetTimeline(matchID string) (*riot.MatchTimeline, bool, error) {
+	var timeline riot.MatchTimeline
+	ok, err := s.readCached(s.timelines, s.timelinePath(matchID), matchID, &timeline)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return &timeline, true, nil
+}
+
+// タイムラインを保存（上限はマッチ詳細と同じ件数）
+func (s *FileStore) PutTimeline(timeline *riot.MatchTimeline) error {
+	matchID := timeline.Metadata.MatchID
+	if matchID == "" {
+		return fmt.Errorf("マッチIDが空のため保存できません")
+	}
+
+	data, err := json.Marshal(timeline)
+	if err != nil {
+		return fmt.Errorf("JSON変換エラー: %w", err)
+	}
+
+	if err := writeFileAtomic(s.timelinePath(matchID), data); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.timelines.touch(matchID)
+	evicted := s.evictLocked()
+	s.mu.Unlock()
+
+	removeFiles(evicted)
+	return nil
+}
+
+// 保存済みのマッチ履歴を取得
+func (s *FileStore) GetPlayerHistory(puuid, query string) (*riot.PlayerHistory, bool, error) {
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+
+	data, err := os.ReadFile(s.playerPath(puuid, query))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("履歴読み込みエラー: %w", err)
+	}
+
+	var history riot.PlayerHistory
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, false, fmt.Errorf("履歴JSON解析エラー: %w", err)
+	}
+
+	return &history, true, nil
+}
+
+// マッチ履歴を保存
+func (s *FileStore) PutPlayerHistory(history *riot.PlayerHistory) error {
+	if history.PUUID == "" {
+		return fmt.Errorf("PUUIDが空のため保存できません")
+	}
+
+	data, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("JSON変換エラー: %w", err)
+	}
+
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+
+	path := s.playerPath(history.PUUID, history.Query)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("ストアディレクトリ作成エラー: %w", err)
+	}
+
+	return writeFileAtomic(path, data)
+}
+
+// 1プレイヤーあたりに保存するランクのスナップショットの上限
+const maxLeagueSnapshots = 1000
+
+// 保存済みのランクのスナップショットを取得（古い順）
+func (s *FileStore) GetLeagueSnapshots(puuid string) ([]riot.LeagueSnapshot, error) {
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+
+	return s.readLeagueSnapshotsLocked(puuid)
+}
+
+// ランクのスナップショットを追加（上限を超えた場合は古いものから削除）
+func (s *FileStore) PutLeagueSnapshot(snapshot *riot.LeagueSnapshot) error {
+	if snapshot.PUUID == "" {
+		return fmt.Errorf("PUUIDが空のため保存できません")
+	}
+
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+
+	snapshots, err := s.readLeagueSnapshotsLocked(snapshot.PUUID)
+	if err != nil {
+		return err
+	}
+
+	snapshots = append(snapshots, *snapshot)
+	if len(snapshots) > maxLeagueSnapshots {
+		snapshots = snapshots[len(snapshots)-maxLeagueSnapshots:]
+	}
+
+	data, err := json.Marshal(snapshots)
+	if err != nil {
+		return fmt.Errorf("JSON変換エラー: %w", err)
+	}
+
+	return writeFileAtomic(s.leaguePath(snapshot.PUUID), data)
+}
+
+func (s *FileStore) readLeagueSnapshotsLocked(puuid string) ([]riot.LeagueSnapshot, error) {
+	data, err := os.ReadFile(s.leaguePath(puuid))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ランク履歴読み込みエラー: %w", err)
+	}
+
+	var snapshots []riot.LeagueSnapshot
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return nil, fmt.Errorf("ランク履歴JSON解析エラー: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// キャッシュの利用状況を取得
+func (s *FileStore) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return Stats{
+		Hits:    s.hits,
+		Misses:  s.misses,
+		Entries: s.entries.len(),
+
+		TimelineEntries: s.timelines.len(),
+	}
+}
+
+// 上限を超えた分を最も長く使われていない順に索引から除外し、削除するファイルのパスを返す
+func (s *FileStore) evictLocked() []string {
+	if s.maxMatches <= 0 {
+		return nil
+	}
+
+	var paths []string
+	for _, matchID := range s.entries.evict(s.maxMatches) {
+		paths = append(paths, s.matchPath(matchID))
+	}
+	for _, matchID := range s.timelines.evict(s.maxMatches) {
+		paths = append(paths, s.timelinePath(matchID))
+	}
+	return paths
+}
+
+func removeFiles(paths []string) {
+	for _, path := range paths {
+		os.Remove(path)
+	}
+}
+
+// 一時ファイル経由で書き込み、途中で中断されても壊れたファイルを残さない
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("一時ファイル作成エラー: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("ファイル書き込みエラー: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("ファイル書き込みエラー: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("ファイル書き込みエラー: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MicronGit/Summoner-Analysis/internal/riot"
+)
+
+func testMatch(matchID string) *riot.MatchDetail {
+	var match riot.MatchDetail
+	match.Metadata.MatchID = matchID
+	match.Info.GameDuration = 1800
+	return &match
+}
+
+func newTestStore(t *testing.T, dir string, maxMatches int) *FileStore {
+	t.Helper()
+
+	s, err := NewFileStore(dir, maxMatches)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	return s
+}
+
+// 索引に残っているマッチIDを最近使用した順に返す
+func indexedIDs(s *FileStore) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []string
+	for element := s.entries.order.Front(); element != nil; element = element.Next() {
+		ids = append(ids, element.Value.(string))
+	}
+	return ids
+}
+
+func TestFileStoreEviction(t *testing.T) {
+	type op struct {
+		put bool // false の場合は GetMatch
+		id  string
+	}
+
+	tests := []struct {
+		name       string
+		maxMatches int
+		ops        []op
+		want       []string // 最近使用した順
+		evicted    []string // ファイルも削除されていること
+	}{
+		{
+			name:       "上限なし",
+			maxMatches: 0,
+			ops:        []op{{true, "A"}, {true, "B"}, {true, "C"}},
+			want:       []string{"C", "B", "A"},
+		},
+		{
+			name:       "保存順に削除",
+			maxMatches: 2,
+			ops:        []op{{true, "A"}, {true, "B"}, {true, "C"}},
+			want:       []string{"C", "B"},
+			evicted:    []string{"A"},
+		},
+		{
+			name:       "読み込んだものは残す",
+			maxMatches: 2,
+			ops:        []op{{true, "A"}, {true, "B"}, {false, "A"}, {true, "C"}},
+			want:       []string{"C", "A"},
+			evicted:    []string{"B"},
+		},
+		{
+			name:       "再保存は使用扱い",
+			maxMatches: 2,
+			ops:        []op{{true, "A"}, {true, "B"}, {true, "A"}, {true, "C"}},
+			want:       []string{"C", "A"},
+			evicted:    []string{"B"},
+		},
+		{
+			name:       "ミスは順序を変えない",
+			maxMatches: 2,
+			ops:        []op{{true, "A"}, {true, "B"}, {false, "X"}, {true, "C"}},
+			want:       []string{"C", "B"},
+			evicted:    []string{"A"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore(t, t.TempDir(), tt.maxMatches)
+
+			for _, o := range tt.ops {
+				if o.put {
+					if err := s.PutMatch(testMatch(o.id)); err != nil {
+						t.Fatalf("PutMatch(%s): %v", o.id, err)
+					}
+					continue
+				}
+				if _, _, err := s.GetMatch(o.id); err != nil {
+					t.Fatalf("GetMatch(%s): %v", o.id, err)
+				}
+			}
+
+			if got := indexedIDs(s); !slices.Equal(got, tt.want) {
+				t.Errorf("索引 = %v, want %v", got, tt.want)
+			}
+			for _, id := range tt.evicted {
+				if _, err := os.Stat(s.matchPath(id)); !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("%s のファイルが削除されていない (err = %v)", id, err)
+				}
+				if _, ok, _ := s.GetMatch(id); ok {
+					t.Errorf("GetMatch(%s) がヒットした", id)
+				}
+			}
+		})
+	}
+}
+
+func TestFileStoreStats(t *testing.T) {
+	tests := []struct {
+		name       string
+		put        []string
+		get        []string
+		wantHits   int64
+		wantMisses int64
+	}{
+		{name: "空", get: []string{"A"}, wantMisses: 1},
+		{name: "ヒット", put: []string{"A"}, get: []string{"A", "A"}, wantHits: 2},
+		{name: "混在", put: []string{"A", "B"}, get: []string{"A", "C", "B", "D"}, wantHits: 2, wantMisses: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore(t, t.TempDir(), 0)
+
+			for _, id := range tt.put {
+				if err := s.PutMatch(testMatch(id)); err != nil {
+					t.Fatalf("PutMatch(%s): %v", id, err)
+				}
+			}
+			for _, id := range tt.get {
+				detail, ok, err := s.GetMatch(id)
+				if err != nil {
+					t.Fatalf("GetMatch(%s): %v", id, err)
+				}
+				if ok && detail.Metadata.MatchID != id {
+					t.Errorf("GetMatch(%s) = %s", id, detail.Metadata.MatchID)
+				}
+			}
+
+			stats := s.Stats()
+			if stats.Hits != tt.wantHits || stats.Misses != tt.wantMisses {
+				t.Errorf("hits/misses = %d/%d, want %d/%d", stats.Hits, stats.Misses, tt.wantHits, tt.wantMisses)
+			}
+			if stats.Entries != len(tt.put) {
+				t.Errorf("entries = %d, want %d", stats.Entries, len(tt.put))
+			}
+		})
+	}
+}
+
+func TestFileStoreReload(t *testing.T) {
+	dir := t.TempDir()
+	s := newTestStore(t, dir, 0)
+
+	// 更新時刻の古い順に A, B, C
+	base := time.Now().Add(-time.Hour)
+	for i, id := range []string{"A", "B", "C"} {
+		if err := s.PutMatch(testMatch(id)); err != nil {
+			t.Fatalf("PutMatch(%s): %v", id, err)
+		}
+		modTime := base.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(s.matchPath(id), modTime, modTime); err != nil {
+			t.Fatalf("Chtimes: %v", err)
+		}
+	}
+
+	// 壊れたファイルはミスとして扱い削除する
+	if err := os.WriteFile(s.matchPath("broken"), []byte("{"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	reloaded := newTestStore(t, dir, 3)
+
+	if got, want := indexedIDs(reloaded), []string{"broken", "C", "B"}; !slices.Equal(got, want) {
+		t.Errorf("索引 = %v, want %v", got, want)
+	}
+
+	if _, ok, err := reloaded.GetMatch("broken"); ok || err != nil {
+		t.Errorf("GetMatch(broken) = %v, %v, want miss", ok, err)
+	}
+	if _, err := os.Stat(reloaded.matchPath("broken")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("壊れたファイルが削除されていない (err = %v)", err)
+	}
+	if _, ok, err := reloaded.GetMatch("B"); !ok || err != nil {
+		t.Errorf("GetMatch(B) = %v, %v, want hit", ok, err)
+	}
+}
+
+func TestFileStoreConcurrentGet(t *testing.T) {
+	s := newTestStore(t, t.TempDir(), 0)
+	if err := s.PutMatch(testMatch("A")); err != nil {
+		t.Fatalf("PutMatch: %v", err)
+	}
+
+	const readers = 16
+	var wg sync.WaitGroup
+	for range readers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok, err := s.GetMatch("A"); !ok || err != nil {
+				t.Errorf("GetMatch = %v, %v", ok, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if stats := s.Stats(); stats.Hits != readers {
+		t.Errorf("hits = %d, want %d", stats.Hits, readers)
+	}
+}
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"container/list"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// 保存済みのマッチIDを使用順に保持する索引（先頭が最近使用したもの）
+// 上限を超えた場合は末尾から削除するため、削除のたびに全件を走査しない
+type lruIndex struct {
+	order    *list.List               // 値はマッチID
+	elements map[string]*list.Element // マッチID -> order の要素
+}
+
+func newLRUIndex() *lruIndex {
+	return &lruIndex{
+		order:    list.New(),
+		elements: make(map[string]*list.Element),
+	}
+}
+
+func (x *lruIndex) len() int {
+	return x.order.Len()
+}
+
+func (x *lruIndex) contains(id string) bool {
+	_, ok := x.elements[id]
+	return ok
+}
+
+// 最近使用したものとして先頭に移動（未登録の場合は追加）
+func (x *lruIndex) touch(id string) {
+	if element, ok := x.elements[id]; ok {
+		x.order.MoveToFront(element)
+		return
+	}
+	x.elements[id] = x.order.PushFront(id)
+}
+
+func (x *lruIndex) remove(id string) {
+	if element, ok := x.elements[id]; ok {
+		x.order.Remove(element)
+		delete(x.elements, id)
+	}
+}
+
+// 件数が limit 以下になるまで最も長く使われていないものから削除し、削除したIDを返す
+func (x *lruIndex) evict(limit int) []string {
+	var evicted []string
+	for x.order.Len() > limit {
+		id := x.order.Remove(x.order.Back()).(string)
+		delete(x.elements, id)
+		evicted = append(evicted, id)
+	}
+	return evicted
+}
+
+// ディレクトリ内の "<エスケープ済みマッチID>.json" を更新時刻の古い順に索引へ読み込む
+func loadIndex(dir string, index *lruIndex) error {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("ストアディレクトリ読み込みエラー: %w", err)
+	}
+
+	type indexedFile struct {
+		matchID string
+		modTime time.Time
+	}
+	var indexed []indexedFile
+
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+
+		matchID, err := url.PathUnescape(strings.TrimSuffix(name, ".json"))
+		if err != nil {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		indexed = append(indexed, indexedFile{matchID: matchID, modTime: info.ModTime()})
+	}
+
+	// 新しいものほど先頭に来るよう古い順に登録
+	slices.SortFunc(indexed, func(a, b indexedFile) int {
+		return a.modTime.Compare(b.modTime)
+	})
+	for _, file := range indexed {
+		index.touch(file.matchID)
+	}
+
+	return nil
+}
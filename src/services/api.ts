@@ -2,7 +2,13 @@ import axios, { AxiosResponse } from 'axios'
 import type {
   AnalysisRequest,
   AnalysisResponse,
-  PlayerStats
+  LiveGameReport,
+  LiveGameResponse,
+  PartialStats,
+  Platform,
+  PlayerStats,
+  ProgressEvent,
+  StreamError
 } from '../types'
 
 const api = axios.create({
@@ -10,6 +16,29 @@ const api = axios.create({
   timeout: 15 * 60 * 1000, // 15分タイムアウト
 })
 
+// 分析の進捗の通知先
+export interface AnalysisStreamHandlers {
+  onProgress?: (event: ProgressEvent) => void
+  onPartial?: (stats: PartialStats) => void
+}
+
+// 分析エラーのステータスコードに対応するメッセージ
+const analysisErrorMessage = (status: number | undefined, message?: string): string => {
+  if (status === 404) {
+    return 'プレイヤーが見つかりませんでした。名前とタグラインを確認してください。'
+  }
+  if (status === 403) {
+    return 'API キーが無効です。設定を確認してください。'
+  }
+  if (status === 429) {
+    return 'レート制限に達しました。しばらく待ってから再試行してください。'
+  }
+  if (status === 504) {
+    return '分析がタイムアウトしました。試合数を減らして再試行してください。'
+  }
+  return message || 'サーバーエラーが発生しました'
+}
+
 export class ApiService {
   // プレイヤー分析実行
   static async analyzePlayer(request: AnalysisRequest): Promise<PlayerStats> {
@@ -30,12 +59,77 @@ export class ApiService {
         if (error.code === 'ECONNABORTED') {
           throw new Error('リクエストがタイムアウトしました。時間をおいて再試行してください。')
         }
-        if (error.response?.status === 404) {
-          throw new Error('プレイヤーが見つかりませんでした。名前とタグラインを確認してください。')
+        throw new Error(analysisErrorMessage(error.response?.status, error.response?.data?.error))
+      }
+      throw error
+    }
+  }
+
+  // プレイヤー分析実行（Server-Sent Events で進捗を受け取る）
+  static analyzePlayerStream(request: AnalysisRequest, handlers: AnalysisStreamHandlers = {}): Promise<PlayerStats> {
+    const params = new URLSearchParams({
+      gameName: request.gameName,
+      tagLine: request.tagLine,
+      gameType: request.gameType,
+      matchCount: String(request.matchCount)
+    })
+    if (request.region) params.set('region', request.region)
+    if (request.platform) params.set('platform', request.platform)
+    if (request.includeTimelines) params.set('includeTimelines', 'true')
+
+    return new Promise((resolve, reject) => {
+      const source = new EventSource(`/api/analyze/stream?${params}`)
+
+      source.addEventListener('progress', (e) => {
+        handlers.onProgress?.(JSON.parse((e as MessageEvent).data) as ProgressEvent)
+      })
+
+      source.addEventListener('partial', (e) => {
+        handlers.onPartial?.(JSON.parse((e as MessageEvent).data) as PartialStats)
+      })
+
+      source.addEventListener('result', (e) => {
+        // 閉じないと EventSource が自動で再接続し、分析をやり直してしまう
+        source.close()
+
+        const response = JSON.parse((e as MessageEvent).data) as AnalysisResponse
+        if (!response.success || !response.data) {
+          reject(new Error(response.error || 'データが取得できませんでした'))
+          return
         }
-        if (error.response?.status === 403) {
-          throw new Error('API キーが無効です。設定を確認してください。')
+        resolve(response.data)
+      })
+
+      // サーバーからの error イベント（data あり）と接続エラー（data なし）の両方を受け取る
+      source.addEventListener('error', (e) => {
+        source.close()
+
+        const data = e instanceof MessageEvent ? e.data : undefined
+        if (!data) {
+          reject(new Error('サーバーとの接続が切断されました。時間をおいて再試行してください。'))
+          return
         }
+
+        const streamError = JSON.parse(data) as StreamError
+        reject(new Error(analysisErrorMessage(streamError.status, streamError.error)))
+      })
+    })
+  }
+
+  // 試合中の参加者全員の直近成績を取得（1人あたりの試合数はサーバーの既定値）
+  static async getLiveGame(gameName: string, tagLine: string, platform: Platform): Promise<LiveGameReport> {
+    try {
+      const path = `/live/${encodeURIComponent(gameName)}/${encodeURIComponent(tagLine)}`
+      const response: AxiosResponse<LiveGameResponse> = await api.get(path, { params: { platform } })
+
+      if (!response.data.success || !response.data.data) {
+        throw new Error(response.data.error || '試合情報を取得できませんでした')
+      }
+
+      return response.data.data
+    } catch (error) {
+      if (axios.isAxiosError(error)) {
+        // 404 はアカウントが見つからない場合と試合中でない場合があるためサーバーのメッセージを表示
         if (error.response?.status === 429) {
           throw new Error('レート制限に達しました。しばらく待ってから再試行してください。')
         }
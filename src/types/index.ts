@@ -1,16 +1,24 @@
 // リージョン定義
-export type Region = 'asia' | 'americas' | 'europe'
+export type Region = 'asia' | 'americas' | 'europe' | 'sea'
+
+// プラットフォーム（サーバー）定義
+export type Platform =
+  | 'jp1' | 'kr'
+  | 'na1' | 'br1' | 'la1' | 'la2'
+  | 'euw1' | 'eun1' | 'tr1' | 'ru' | 'me1'
+  | 'oc1' | 'ph2' | 'sg2' | 'th2' | 'tw2' | 'vn2'
 
 // ゲームタイプ定義
-export type GameType = 'ranked' | 'normal' | 'aram' | 'all'
+export type GameType = 'ranked' | 'solo' | 'flex' | 'normal' | 'aram' | 'arena' | 'all'
 
 // 検索フォーム
 export interface SearchForm {
   gameName: string
   tagLine: string
-  region: Region
+  platform: Platform
   gameType: GameType
   matchCount: number
+  includeTimelines: boolean
 }
 
 // アカウント情報
@@ -32,6 +40,7 @@ export interface KDAStats {
 export interface ChampionStats {
   championName: string
   gamesPlayed: number
+  wins: number
   winRate: number
   averageKDA: KDAStats
 }
@@ -56,6 +65,211 @@ export interface RecentFormStats {
   }
 }
 
+// 昇格戦の進行状況
+export interface MiniSeries {
+  losses: number
+  progress: string
+  target: number
+  wins: number
+}
+
+// リーグエントリー（現在のランク）
+export interface LeagueEntry {
+  leagueId: string
+  puuid: string
+  queueType: string
+  tier: string
+  rank: string
+  leaguePoints: number
+  wins: number
+  losses: number
+  hotStreak: boolean
+  veteran: boolean
+  freshBlood: boolean
+  inactive: boolean
+  miniSeries?: MiniSeries
+}
+
+// 現在のランク（未ランクのキューは null）
+export interface RankInfo {
+  solo: LeagueEntry | null
+  flex: LeagueEntry | null
+}
+
+// LP 推移の1点
+export interface LPPoint {
+  time: string
+  tier: string
+  rank: string
+  leaguePoints: number
+  totalLP: number
+  wins: number
+  losses: number
+}
+
+// 1試合あたりの推定LP増減
+export interface LPGameEstimate {
+  matchId: string
+  gameEnd: string
+  championName: string
+  win: boolean
+  estimatedLPChange: number
+}
+
+// キューごとの LP 推移
+export interface QueueLPHistory {
+  queueType: string
+  series: LPPoint[]
+  games: LPGameEstimate[]
+  netLPChange: number
+  averageLPGain: number
+  averageLPLoss: number
+}
+
+export interface LPHistoryReport {
+  queues: QueueLPHistory[]
+}
+
+// チャンピオンごとのマスタリーと直近成績
+export interface ChampionMasteryStats {
+  championId: number
+  championName: string
+  masteryLevel: number
+  masteryPoints: number
+  lastPlayTime: string
+  recentGames: number
+  recentWinRate: number
+  recentKDA: number
+  flag?: 'struggling' | 'promising'
+}
+
+// マスタリーと直近成績の比較
+export interface MasteryReport {
+  champions: ChampionMasteryStats[]
+  struggling: ChampionMasteryStats[]
+  promising: ChampionMasteryStats[]
+}
+
+// レーン戦の平均値（差分は対面との比較）
+export interface LaningStats {
+  games: number
+  averageCSAt10: number
+  averageCSDiffAt10: number
+  averageGoldDiffAt10: number
+  averageXPDiffAt10: number
+  averageGoldDiffAt15: number
+  averageXPDiffAt15: number
+  laneLeadRate: number
+  averageFirstDeathMinute: number
+  deathlessGames: number
+}
+
+// 1試合分のレーン戦
+export interface MatchLaning {
+  matchId: string
+  championName: string
+  position: string
+  opponentChampion: string
+  win: boolean
+  csAt10: number
+  csDiffAt10: number
+  goldDiffAt10: number
+  xpDiffAt10: number
+  goldDiffAt15?: number
+  xpDiffAt15?: number
+  firstDeathMinute?: number
+}
+
+// レーン戦レポート
+export interface LaningReport {
+  overall: LaningStats
+  byChampion: (LaningStats & { championName: string })[]
+  byPosition: Record<string, LaningStats>
+  matches: MatchLaning[]
+  skippedMatches: number
+}
+
+// ポジションごとの成績（1分あたりの値は試合時間の合計で割った値）
+export interface PositionPerformance {
+  games: number
+  wins: number
+  winRate: number
+  averageKDA: KDAStats
+  csPerMinute: number
+  visionPerMinute: number
+  damagePerMinute: number
+  goldPerMinute: number
+  topChampions: ChampionStats[]
+}
+
+// 対面（同じポジションの敵）のチャンピオンとの組み合わせごとの成績（差分は試合終了時）
+export interface MatchupStats {
+  championName: string
+  opponentChampion: string
+  games: number
+  wins: number
+  winRate: number
+  averageKDA: KDAStats
+  averageGoldDiff: number
+  averageCSDiff: number
+}
+
+export interface ChampionMatchups {
+  championName: string
+  games: number
+  matchups: MatchupStats[]
+  best: MatchupStats[]
+  worst: MatchupStats[]
+}
+
+export interface MatchupReport {
+  matchups: MatchupStats[]
+  byChampion: ChampionMatchups[]
+  skippedMatches: number
+}
+
+// 自分と味方のチャンピオンの組み合わせごとの成績
+export interface ChampionPairStats {
+  championName: string
+  teammateChampion: string
+  games: number
+  wins: number
+  winRate: number
+}
+
+// 2試合以上同じチームだったチームメイトとの成績
+export interface TeammateStats {
+  puuid: string
+  gameName: string
+  tagLine: string
+  games: number
+  wins: number
+  winRate: number
+  gamesApart: number
+  winRateApart: number
+  championPairs: ChampionPairStats[]
+}
+
+export interface SynergyReport {
+  teammates: TeammateStats[]
+}
+
+// チーム内での貢献度（割合は %）
+export interface ContributionStats {
+  games: number
+  averageKillParticipation: number
+  averageDamageShare: number
+  averageGoldShare: number
+  averageDamageTakenShare: number
+  averageVisionPerMinute: number
+}
+
+export interface ContributionReport {
+  overall: ContributionStats
+  byChampion: (ContributionStats & { championName: string })[]
+  byPosition: Record<string, ContributionStats>
+}
+
 // プレイヤー統計
 export interface PlayerStats {
   playerInfo: Account
@@ -63,11 +277,88 @@ export interface PlayerStats {
   matchType: string
   totalMatches: number
   winRate: number
+  rank?: RankInfo
   averageKDA: KDAStats
   rankPerformance: RankStats
   mostPlayedChampions: ChampionStats[]
   positionStats: Record<string, number>
+  positionPerformance?: Record<string, PositionPerformance>
   recentForm: RecentFormStats
+  contribution?: ContributionReport
+  matchups?: MatchupReport
+  synergy?: SynergyReport
+  lpHistory?: LPHistoryReport
+  mastery?: MasteryReport
+  laning?: LaningReport
+  matches?: MatchAssets[]
+}
+
+// 静的データで名前・アイコンに変換したID
+export interface ResolvedID {
+  id: number
+  name: string
+  icon: string // /api/static/ 以下の画像のURL（画像を取り込んでいない場合は存在しない）
+}
+
+// 試合ごとのバン・アイテム・ルーン・サモナースペル（静的データがある場合のみ）
+export interface MatchAssets {
+  matchId: string
+  gameVersion: string
+  dataVersion: string
+  bans: ResolvedID[]
+  items: ResolvedID[]
+  runes: ResolvedID[]
+  summonerSpells: ResolvedID[]
+}
+
+// 試合中の参加者の直近成績
+export interface ParticipantForm {
+  recentGames: number
+  recentWins: number
+  recentWinRate: number
+  recentKDA: number
+  mainChampion: string
+  mainChampionGames: number
+  currentChampionGames: number
+  currentChampionWinRate: number
+}
+
+// 試合中の参加者
+export interface LiveParticipant {
+  puuid: string
+  riotId: string
+  teamId: number
+  championId: number
+  championName?: string
+  bot: boolean
+  spell1Id: number
+  spell2Id: number
+  perks: {
+    perkIds: number[]
+    perkStyle: number
+    perkSubStyle: number
+  }
+  rank: LeagueEntry | null
+  form?: ParticipantForm
+  error?: string
+}
+
+// 進行中の試合
+export interface LiveGameReport {
+  gameId: number
+  gameMode: string
+  queueId: number
+  mapId: number
+  startTime: string
+  gameLength: number
+  bannedChampions: { championId: number; pickTurn: number; teamId: number }[]
+  participants: LiveParticipant[]
+}
+
+export interface LiveGameResponse {
+  success: boolean
+  data?: LiveGameReport
+  error?: string
 }
 
 // API レスポンス
@@ -81,9 +372,51 @@ export interface AnalysisResponse {
 export interface AnalysisRequest {
   gameName: string
   tagLine: string
-  region: Region
+  region?: Region
+  platform?: Platform
   gameType: GameType
   matchCount: number
+  includeTimelines?: boolean
+}
+
+// 分析の進捗イベント（/api/analyze/stream の progress イベント）
+export type ProgressEventType =
+  | 'phase-start' | 'progress' | 'phase-done' | 'skipped'
+  | 'rate-limit-wait' | 'retry' | 'info' | 'warning'
+
+export type ProgressPhase = 'match-ids' | 'league' | 'mastery' | 'matches' | 'timelines'
+
+export interface ProgressEvent {
+  type: ProgressEventType
+  phase?: ProgressPhase
+  fetched: number
+  total: number
+  skipped: number
+  elapsedSeconds: number
+  etaSeconds: number
+  waitSeconds?: number
+  routing?: string
+  method?: string
+  attempt?: number
+  matchId?: string
+  message?: string
+  error?: string
+}
+
+// 取得済みの試合の途中集計（partial イベント）
+export interface PartialStats {
+  matches: number
+  wins: number
+  winRate: number
+  averageKDA: KDAStats
+}
+
+// 分析の失敗（error イベント、status は /api/analyze の場合の HTTP ステータスコード）
+export interface StreamError {
+  success: false
+  error: string
+  status: number
+  retryAfter?: number
 }
 
 // 選択肢